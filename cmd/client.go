@@ -9,6 +9,8 @@ import (
 )
 
 var addrSrv *string
+var addrsSrv *[]string
+var readYourWritesSrv *bool
 var policySrv *string
 var timeoutSrv *time.Duration
 
@@ -18,8 +20,10 @@ var clientCmd = &cobra.Command{
 	Short: "Run a SporeDB client in CLI",
 	Run: func(cmd *cobra.Command, args []string) {
 		cli := &endpoint.Client{
-			Addr:    *addrSrv,
-			Timeout: *timeoutSrv,
+			Addr:           *addrSrv,
+			Addrs:          *addrsSrv,
+			Timeout:        *timeoutSrv,
+			ReadYourWrites: *readYourWritesSrv,
 		}
 
 		err := cli.Connect()
@@ -34,6 +38,8 @@ var clientCmd = &cobra.Command{
 func init() {
 	RootCmd.AddCommand(clientCmd)
 	addrSrv = clientCmd.Flags().StringP("server", "s", "localhost:4200", "server address")
+	addrsSrv = clientCmd.Flags().StringSlice("fallback", nil, "other server addresses, for load balancing and failover")
+	readYourWritesSrv = clientCmd.Flags().Bool("read-your-writes", false, "read from servers which have applied the submitted transactions")
 	timeoutSrv = clientCmd.Flags().DurationP("timeout", "t", 10*time.Second, "connection timeout")
 	policySrv = clientCmd.Flags().StringP("policy", "p", "none", "default policy to use when submitting")
 }
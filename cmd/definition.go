@@ -0,0 +1,297 @@
+package cmd
+
+import (
+	"encoding/base64"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/spf13/viper"
+
+	"gitlab.com/SporeDB/sporedb/db"
+)
+
+// policyDefinition is the human-friendly representation of a policy.
+// It can be written in YAML or JSON, and references endorsers by their
+// keyring identity ("self" standing for the local key).
+//
+//	uuid: accounts
+//	comment: Bank accounts
+//	endorsers: [self, bob, carol]
+//	quorum: 3
+//	timeout: 30s
+//	delay: 1h
+//	claims: [account/]
+//	emitter_quota: {max_size: 1048576, max_spores: 100, window: 1m, max_staged: 10}
+//	specs:
+//	  - regex: ^account/
+//	    max_size: 64
+//	    allowed_operations: [ADD]
+//	    rules:
+//	      min: 0
+//	  - name: account/vault
+//	    cosigners: [bob, carol]
+//	    cosign_quorum: 1
+//	invariants:
+//	  - name: conservation
+//	    expression: delta(account/*) == 0
+type policyDefinition struct {
+	Uuid         string
+	Comment      string
+	Endorsers    []string
+	Quorum       uint64
+	Timeout      time.Duration
+	Delay        time.Duration
+	GracePeriod  time.Duration `mapstructure:"grace_period"`
+	MaxSize      uint64        `mapstructure:"max_size"`
+	MaxOpSize    uint64        `mapstructure:"max_op_size"`
+	Specs        []specDefinition
+	Invariants   []*db.Invariant
+	Claims       []string
+	EmitterQuota *quotaDefinition `mapstructure:"emitter_quota"`
+}
+
+type quotaDefinition struct {
+	MaxSize   uint64 `mapstructure:"max_size"`
+	MaxSpores uint64 `mapstructure:"max_spores"`
+	Window    time.Duration
+	MaxStaged uint64 `mapstructure:"max_staged"`
+}
+
+type specDefinition struct {
+	Name              string
+	Regex             string
+	MaxSize           uint64   `mapstructure:"max_size"`
+	AllowedOperations []string `mapstructure:"allowed_operations"`
+	Rules             *rulesDefinition
+	Cosigners         []string
+	CosignQuorum      uint64 `mapstructure:"cosign_quorum"`
+}
+
+type rulesDefinition struct {
+	Numeric    bool
+	Min        string
+	Max        string
+	Regex      string
+	JSONSchema string `mapstructure:"json_schema"`
+	MaxMembers uint64 `mapstructure:"max_members"`
+}
+
+const selfIdentity = "self"
+
+// readPolicyFile reads either a JSON policy, or a YAML / JSON policy definition.
+func readPolicyFile(p string) (*db.Policy, error) {
+	if strings.ToLower(filepath.Ext(p)) == ".json" {
+		if policy, err := readPolicy(p); err == nil {
+			return policy, nil
+		}
+	}
+
+	v := viper.New()
+	v.SetConfigFile(p)
+	if err := v.ReadInConfig(); err != nil {
+		return nil, err
+	}
+
+	def := &policyDefinition{}
+	if err := v.Unmarshal(def); err != nil {
+		return nil, err
+	}
+
+	return def.build()
+}
+
+func (def *policyDefinition) build() (*db.Policy, error) {
+	p := &db.Policy{
+		Uuid:       def.Uuid,
+		Comment:    def.Comment,
+		Quorum:     def.Quorum,
+		MaxSize:    def.MaxSize,
+		MaxOpSize:  def.MaxOpSize,
+		Invariants: def.Invariants,
+		Claims:     def.Claims,
+	}
+
+	if def.Timeout > 0 {
+		p.Timeout = ptypes.DurationProto(def.Timeout)
+	}
+	if def.GracePeriod > 0 {
+		p.GracePeriod = ptypes.DurationProto(def.GracePeriod)
+	}
+	if def.Delay > 0 {
+		p.Delay = ptypes.DurationProto(def.Delay)
+	}
+
+	if q := def.EmitterQuota; q != nil {
+		p.EmitterQuota = &db.EmitterQuota{
+			MaxSize:   q.MaxSize,
+			MaxSpores: q.MaxSpores,
+			Window:    ptypes.DurationProto(q.Window),
+			MaxStaged: q.MaxStaged,
+		}
+	}
+
+	var err error
+	if p.Endorsers, err = resolveIdentities(def.Endorsers); err != nil {
+		return nil, err
+	}
+
+	for i, s := range def.Specs {
+		spec := &db.OSpec{MaxSize: s.MaxSize, CosignQuorum: s.CosignQuorum}
+		switch {
+		case s.Name != "" && s.Regex != "":
+			return nil, fmt.Errorf("spec #%d: name and regex are mutually exclusive", i+1)
+		case s.Name != "":
+			spec.Key = &db.OSpec_Name{Name: s.Name}
+		default:
+			spec.Key = &db.OSpec_Regex{Regex: s.Regex}
+		}
+
+		for _, op := range s.AllowedOperations {
+			value, ok := db.Operation_Op_value[strings.ToUpper(op)]
+			if !ok {
+				return nil, fmt.Errorf("spec #%d: unknown operation %q", i+1, op)
+			}
+			spec.AllowedOperations = append(spec.AllowedOperations, db.Operation_Op(value))
+		}
+
+		if spec.Cosigners, err = resolveIdentities(s.Cosigners); err != nil {
+			return nil, fmt.Errorf("spec #%d: %v", i+1, err)
+		}
+
+		if r := s.Rules; r != nil {
+			spec.Rules = &db.ValueRules{
+				Numeric:    r.Numeric,
+				Min:        r.Min,
+				Max:        r.Max,
+				Regex:      r.Regex,
+				JsonSchema: r.JSONSchema,
+				MaxMembers: r.MaxMembers,
+			}
+		}
+
+		p.Specs = append(p.Specs, spec)
+	}
+
+	return p, nil
+}
+
+// resolveIdentities returns the public keys of keyring identities.
+func resolveIdentities(identities []string) (keys []*db.Endorser, err error) {
+	if len(identities) == 0 {
+		return
+	}
+
+	keyRing := getKeyRing()
+	for _, identity := range identities {
+		comment := identity
+		if identity == selfIdentity {
+			identity, comment = "", ""
+		}
+
+		data, _, err := keyRing.GetPublic(identity)
+		if err != nil {
+			return nil, err
+		}
+
+		keys = append(keys, &db.Endorser{Public: data, Comment: comment})
+	}
+	return
+}
+
+// describePolicy returns a line-by-line representation of the policy,
+// suitable for display and comparison.
+func describePolicy(p *db.Policy) []string {
+	lines := []string{
+		"uuid: " + p.Uuid,
+		"comment: " + p.Comment,
+		fmt.Sprintf("quorum: %d/%d", p.Quorum, len(p.Endorsers)),
+	}
+
+	if d, err := ptypes.Duration(p.Timeout); err == nil {
+		lines = append(lines, "timeout: "+d.String())
+	}
+	if d, err := ptypes.Duration(p.GracePeriod); err == nil {
+		lines = append(lines, "grace period: "+d.String())
+	}
+	if d, err := ptypes.Duration(p.Delay); err == nil {
+		lines = append(lines, "delay: "+d.String())
+	}
+	if p.MaxSize > 0 {
+		lines = append(lines, fmt.Sprintf("max size: %d", p.MaxSize))
+	}
+	if p.MaxOpSize > 0 {
+		lines = append(lines, fmt.Sprintf("max operation size: %d", p.MaxOpSize))
+	}
+
+	if q := p.EmitterQuota; q != nil {
+		lines = append(lines, "emitter quota: "+q.String())
+	}
+
+	for _, e := range p.Endorsers {
+		line := "endorser: " + base64.StdEncoding.EncodeToString(e.Public)
+		if e.Comment != "" {
+			line += " (" + e.Comment + ")"
+		}
+		lines = append(lines, line)
+	}
+
+	for _, s := range p.Specs {
+		line := "spec: " + s.KeyString()
+		if s.MaxSize > 0 {
+			line += fmt.Sprintf(" max_size=%d", s.MaxSize)
+		}
+		if len(s.AllowedOperations) > 0 {
+			ops := make([]string, len(s.AllowedOperations))
+			for i, op := range s.AllowedOperations {
+				ops[i] = op.String()
+			}
+			line += " allowed_operations=" + strings.Join(ops, ",")
+		}
+		if s.Rules != nil {
+			line += " rules={" + s.Rules.String() + "}"
+		}
+		if len(s.Cosigners) > 0 {
+			cosigners := make([]string, len(s.Cosigners))
+			for i, c := range s.Cosigners {
+				cosigners[i] = base64.StdEncoding.EncodeToString(c.Public)
+				if c.Comment != "" {
+					cosigners[i] += " (" + c.Comment + ")"
+				}
+			}
+			quorum := s.CosignQuorum
+			if quorum == 0 {
+				quorum = uint64(len(s.Cosigners))
+			}
+			line += fmt.Sprintf(" cosigners=%d/%d{%s}", quorum, len(s.Cosigners), strings.Join(cosigners, ","))
+		}
+		lines = append(lines, line)
+	}
+
+	for _, c := range p.Claims {
+		lines = append(lines, "claim: "+c)
+	}
+
+	for _, i := range p.Invariants {
+		lines = append(lines, "invariant: "+i.Name+" ("+i.Expression+")")
+	}
+
+	return lines
+}
+
+// substractLines returns lines of a that are not in b, keeping their order.
+func substractLines(a, b []string) (r []string) {
+	set := make(map[string]bool, len(b))
+	for _, line := range b {
+		set[line] = true
+	}
+
+	for _, line := range a {
+		if !set[line] {
+			r = append(r, line)
+		}
+	}
+	return
+}
@@ -1,11 +1,13 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"path"
 	"strconv"
+	"time"
 
 	"go.uber.org/zap"
 
@@ -15,9 +17,13 @@ import (
 	"github.com/spf13/viper"
 
 	"gitlab.com/SporeDB/sporedb/db"
+	"gitlab.com/SporeDB/sporedb/db/api"
+	endpoint "gitlab.com/SporeDB/sporedb/db/client"
 )
 
 var policyPath *string
+var policyAddr *string
+var policyTimeout *time.Duration
 
 // policyCmd represents the policy command
 var policyCmd = &cobra.Command{
@@ -73,32 +79,185 @@ var policyCreateCmd = &cobra.Command{
 		}
 
 		p.Quorum = uint64(userQuorum)
-		m := &jsonpb.Marshaler{EmitDefaults: true, Indent: "  ", OrigName: true}
-		s, err := m.MarshalToString(p)
+		writePolicy(p)
+	},
+}
+
+var policyValidateCmd = &cobra.Command{
+	Use:   "validate [file]",
+	Short: "Check that a policy or policy definition is safe and usable",
+	Run: func(cmd *cobra.Command, args []string) {
+		policy, err := readPolicyFile(getArg(cmd, args, 0))
+		check(err)
+
+		errs, warnings := policy.Validate()
+		for _, w := range warnings {
+			fmt.Println("warning:", w)
+		}
+		for _, e := range errs {
+			fmt.Println("error:", e)
+		}
+
+		if len(errs) > 0 {
+			check(fmt.Errorf("policy %q is invalid", policy.Uuid))
+		}
+		fmt.Printf("Policy %q is valid (tolerates %d faulty endorsers)\n", policy.Uuid, policy.FaultTolerance())
+	},
+}
+
+var policyShowCmd = &cobra.Command{
+	Use:   "show [file]",
+	Short: "Display a policy or policy definition",
+	Run: func(cmd *cobra.Command, args []string) {
+		policy, err := readPolicyFile(getArg(cmd, args, 0))
+		check(err)
+
+		for _, line := range describePolicy(policy) {
+			fmt.Println(line)
+		}
+		fmt.Println("fault tolerance:", policy.FaultTolerance())
+	},
+}
+
+var policyDiffCmd = &cobra.Command{
+	Use:   "diff [old] [new]",
+	Short: "Display changes between two policies or policy definitions",
+	Run: func(cmd *cobra.Command, args []string) {
+		oldPolicy, err := readPolicyFile(getArg(cmd, args, 0))
+		check(err)
+		newPolicy, err := readPolicyFile(getArg(cmd, args, 1))
+		check(err)
+
+		oldLines, newLines := describePolicy(oldPolicy), describePolicy(newPolicy)
+		for _, line := range substractLines(oldLines, newLines) {
+			fmt.Println("-", line)
+		}
+		for _, line := range substractLines(newLines, oldLines) {
+			fmt.Println("+", line)
+		}
+	},
+}
+
+var policyBuildCmd = &cobra.Command{
+	Use:   "build [definition]",
+	Short: "Build a JSON policy from a YAML or JSON policy definition",
+	Run: func(cmd *cobra.Command, args []string) {
+		policy, err := readPolicyFile(getArg(cmd, args, 0))
+		check(err)
+
+		errs, _ := policy.Validate()
+		if len(errs) > 0 {
+			check(fmt.Errorf("policy %q is invalid: %v (see policy validate)", policy.Uuid, errs[0]))
+		}
+
+		writePolicy(policy)
+	},
+}
+
+var policyProposeCmd = &cobra.Command{
+	Use:   "propose [file]",
+	Short: "Submit a new or updated policy to the meta-policy endorsers",
+	Run: func(cmd *cobra.Command, args []string) {
+		policy, err := readPolicyFile(getArg(cmd, args, 0))
+		check(err)
+
+		if errs, _ := policy.Validate(); len(errs) > 0 {
+			check(fmt.Errorf("policy %q is invalid: %v (see policy validate)", policy.Uuid, errs[0]))
+		}
+
+		op, err := db.NewPolicyOperation(policy)
 		check(err)
 
-		check(ioutil.WriteFile(path.Join(*policyPath, p.Uuid+".json"), []byte(s), 0600))
+		submitGovernance(op)
+	},
+}
+
+var policyRetireCmd = &cobra.Command{
+	Use:   "retire [uuid]",
+	Short: "Submit the removal of a policy to the meta-policy endorsers",
+	Run: func(cmd *cobra.Command, args []string) {
+		submitGovernance(db.NewPolicyRemoval(getArg(cmd, args, 0)))
 	},
 }
 
+func submitGovernance(op *db.Operation) {
+	cli := &endpoint.Client{
+		Addr:    *policyAddr,
+		Timeout: *policyTimeout,
+	}
+	check(cli.Connect())
+	defer cli.Close()
+
+	ctx, done := context.WithTimeout(context.Background(), *policyTimeout)
+	defer done()
+
+	uuid, err := cli.Submit(ctx, &api.Transaction{
+		Policy:     db.MetaPolicy,
+		Operations: []*db.Operation{op},
+	})
+	check(err)
+	fmt.Println("Transaction:", uuid)
+}
+
 func init() {
 	policyPath = policyCreateCmd.Flags().StringP("path", "p", ".", "policies location")
-	policyCmd.AddCommand(policyCreateCmd)
+	policyAddr = policyProposeCmd.Flags().StringP("server", "s", "localhost:4200", "server address")
+	policyTimeout = policyProposeCmd.Flags().DurationP("timeout", "t", 10*time.Second, "connection timeout")
+	policyBuildCmd.Flags().AddFlagSet(policyCreateCmd.Flags())
+	policyRetireCmd.Flags().AddFlagSet(policyProposeCmd.Flags())
+	policyCmd.AddCommand(
+		policyCreateCmd,
+		policyValidateCmd,
+		policyShowCmd,
+		policyDiffCmd,
+		policyBuildCmd,
+		policyProposeCmd,
+		policyRetireCmd,
+	)
 	RootCmd.AddCommand(policyCmd)
 }
 
-func loadPolicies(database *db.DB) {
-	for _, p := range viper.GetStringSlice("db.policies") {
-		f, err := os.Open(p)
+func readPolicy(p string) (*db.Policy, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	u := &jsonpb.Unmarshaler{}
+	policy := &db.Policy{}
+	return policy, u.Unmarshal(f, policy)
+}
+
+// readPolicies reads every bootstrap policy from JSON files.
+// It fails if one of them cannot be parsed.
+func readPolicies() ([]*db.Policy, error) {
+	paths := viper.GetStringSlice("db.policies")
+	policies := make([]*db.Policy, 0, len(paths))
+	for _, p := range paths {
+		policy, err := readPolicy(p)
 		if err != nil {
-			fmt.Fprintln(os.Stderr, "Unable to load", p, "(cannot open file)")
-			continue
+			return nil, fmt.Errorf("unable to parse %s: %v", p, err)
 		}
-		defer func() { _ = f.Close() }()
+		policies = append(policies, policy)
+	}
+	return policies, nil
+}
+
+func writePolicy(p *db.Policy) {
+	m := &jsonpb.Marshaler{EmitDefaults: true, Indent: "  ", OrigName: true}
+	s, err := m.MarshalToString(p)
+	check(err)
 
-		u := &jsonpb.Unmarshaler{}
-		policy := &db.Policy{}
-		err = u.Unmarshal(f, policy)
+	check(ioutil.WriteFile(path.Join(*policyPath, p.Uuid+".json"), []byte(s), 0600))
+}
+
+// loadPolicies registers bootstrap policies from JSON files.
+// They are overridden by on-ledger policies, if any.
+// It returns the uuids of policies loaded from files.
+func loadPolicies(database *db.DB) (loaded []string, err error) {
+	for _, p := range viper.GetStringSlice("db.policies") {
+		policy, err := readPolicy(p)
 		if err != nil {
 			fmt.Fprintln(os.Stderr, "Unable to parse", p, ":", err)
 			continue
@@ -110,8 +269,12 @@ func loadPolicies(database *db.DB) {
 			continue
 		}
 
+		loaded = append(loaded, policy.Uuid)
 		zap.L().Info("Loaded policy",
 			zap.String("uuid", policy.Uuid),
 		)
 	}
+
+	err = database.LoadStoredPolicies()
+	return
 }
@@ -3,9 +3,11 @@ package cmd
 import (
 	"errors"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"os/signal"
 	"strings"
+	"sync"
 	"syscall"
 	"time"
 
@@ -65,13 +67,24 @@ var serverCmd = &cobra.Command{
 		check(err)
 
 		database := db.NewDB(store, viper.GetString("identity"), keyRing)
-		loadPolicies(database)
+		database.Exclude(viper.GetStringSlice("excluded")...)
+		if viper.IsSet("db.stall_interval") {
+			database.StallInterval = viper.GetDuration("db.stall_interval")
+		}
+
+		filePolicies, err := loadPolicies(database)
+		check(err)
 
+		r := &reloader{database: database, filePolicies: filePolicies}
 		srv := &endpoint.Server{
 			DB:     database,
 			Listen: viper.GetString("api.listen"),
 		}
 
+		if viper.GetBool("api.admin") {
+			srv.Reloader = r.reload
+		}
+
 		rawPeers := viper.GetStringSlice("mycelium.peers")
 		peers := make([]protocol.Node, len(rawPeers))
 		for i, p := range rawPeers {
@@ -87,6 +100,19 @@ var serverCmd = &cobra.Command{
 
 		go startRecover(database, mycelium)
 
+		// Catch SIGHUP
+		go func() {
+			c := make(chan os.Signal, 1)
+			signal.Notify(c, syscall.SIGHUP)
+			for range c {
+				if err := r.reload(); err != nil {
+					zap.L().Error("Unable to reload",
+						zap.Error(err),
+					)
+				}
+			}
+		}()
+
 		// Catch SIGINT and SIGTERM
 		go func() {
 			c := make(chan os.Signal, 2)
@@ -117,6 +143,53 @@ var serverCmd = &cobra.Command{
 	},
 }
 
+// reloader reloads policies and keyring of a running node.
+// Spores endorsed under a reloaded policy keep their original policy until they settle.
+type reloader struct {
+	mutex        sync.Mutex
+	database     *db.DB
+	filePolicies []string
+}
+
+func (r *reloader) reload() error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if err := viper.ReadInConfig(); err != nil {
+		return err
+	}
+
+	// The new policies are parsed before anything is changed
+	policies, err := readPolicies()
+	if err != nil {
+		return err
+	}
+
+	rawKeyRing, err := ioutil.ReadFile(viper.GetString("keyring"))
+	if err != nil {
+		return err
+	}
+
+	if err = r.database.KeyRing.Reload(rawKeyRing); err != nil {
+		return err
+	}
+	r.database.Exclude(viper.GetStringSlice("excluded")...)
+
+	if err = r.database.ReplacePolicies(r.filePolicies, policies); err != nil {
+		return err
+	}
+
+	r.filePolicies = r.filePolicies[:0]
+	for _, p := range policies {
+		r.filePolicies = append(r.filePolicies, p.Uuid)
+	}
+
+	zap.L().Info("Reloaded",
+		zap.Strings("policies", r.database.Policies()),
+	)
+	return nil
+}
+
 func startRecover(database *db.DB, mycelium *myc.Mycelium) {
 	time.Sleep(5 * time.Second) // Artificial delay to ease start-up
 
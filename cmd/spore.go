@@ -0,0 +1,181 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/spf13/cobra"
+
+	"gitlab.com/SporeDB/sporedb/db"
+	endpoint "gitlab.com/SporeDB/sporedb/db/client"
+)
+
+var sporePolicy *string
+var sporePolicies *[]string
+var sporeEmitter *string
+var sporeOperations *[]string
+var sporeDependencies *[]string
+var sporeTimeout *time.Duration
+var sporeValidity *time.Duration
+var sporeDelay *time.Duration
+var sporeCosign *string
+var sporeAddr *string
+var sporeConnTimeout *time.Duration
+
+// sporeCmd represents the spore command
+var sporeCmd = &cobra.Command{
+	Use:   "spore",
+	Short: "Build, sign and submit spores signed by the client's own key",
+	Long: `Build, sign and submit spores signed by the client's own key.
+
+Spores are built and signed as JSON files, so that they can be signed on an
+air-gapped machine and submitted later. The emitter is the identity of the
+signing key in the keyrings of the nodes.
+
+The deadline of the spore is fixed at build time, and may not exceed the
+timeout of its policy. Spores built with a validity instead are signed
+without deadline: the submitting node sets it, up to the timeout of the
+policy and to the end of the validity.`,
+}
+
+var sporeBuildCmd = &cobra.Command{
+	Use:   "build",
+	Short: "Build an unsigned spore",
+	Run: func(cmd *cobra.Command, args []string) {
+		if *sporeEmitter == "" {
+			check(fmt.Errorf("please provide the emitter of the spore"))
+		}
+
+		s := db.NewSpore()
+		s.Policy = *sporePolicy
+		s.Policies = *sporePolicies
+		s.Emitter = *sporeEmitter
+		s.Dependencies = *sporeDependencies
+		if *sporeValidity > 0 {
+			s.SetValidity(*sporeValidity)
+		} else {
+			s.SetTimeout(*sporeTimeout)
+		}
+		if *sporeDelay > 0 {
+			s.SetDelay(*sporeDelay)
+		}
+
+		for _, raw := range *sporeOperations {
+			op, err := parseOperation(raw)
+			check(err)
+			s.Operations = append(s.Operations, op)
+		}
+
+		writeSpore(s)
+	},
+}
+
+var sporeSignCmd = &cobra.Command{
+	Use:   "sign [file]",
+	Short: "Sign a spore as its emitter, or as a cosigner",
+	Run: func(cmd *cobra.Command, args []string) {
+		check(cfgErr)
+		s, err := readSpore(getArg(cmd, args, 0))
+		check(err)
+
+		keyRing := getKeyRing()
+		check(keyRing.UnlockPrivate(getPassword()))
+
+		if *sporeCosign != "" {
+			check(s.Cosign(*sporeCosign, keyRing))
+		} else {
+			check(s.Sign(keyRing))
+		}
+
+		writeSpore(s)
+	},
+}
+
+var sporeSubmitCmd = &cobra.Command{
+	Use:   "submit [file]",
+	Short: "Submit a signed spore",
+	Run: func(cmd *cobra.Command, args []string) {
+		s, err := readSpore(getArg(cmd, args, 0))
+		check(err)
+
+		cli := &endpoint.Client{
+			Addr:    *sporeAddr,
+			Timeout: *sporeConnTimeout,
+		}
+		check(cli.Connect())
+		defer cli.Close()
+
+		ctx, done := context.WithTimeout(context.Background(), *sporeConnTimeout)
+		defer done()
+
+		uuid, err := cli.SubmitSpore(ctx, s)
+		check(err)
+		fmt.Println("Transaction:", uuid)
+	},
+}
+
+func init() {
+	flags := sporeBuildCmd.Flags()
+	sporePolicy = flags.StringP("policy", "p", "none", "policy of the spore")
+	sporePolicies = flags.StringSlice("policies", nil, "additional policies of the spore")
+	sporeEmitter = flags.StringP("emitter", "e", "", "identity of the signing key on the nodes")
+	sporeOperations = flags.StringArrayP("op", "o", nil, `operation, such as "SET key value" (repeatable)`)
+	sporeDependencies = flags.StringSlice("depends", nil, "uuids of spores to be applied first")
+	sporeTimeout = flags.Duration("timeout", db.DefaultMaxTimeout, "spore timeout, up to the policy timeout")
+	sporeValidity = flags.Duration("valid-for", 0, "validity of the spore, whose deadline is then set on submission")
+	sporeDelay = flags.Duration("delay", 0, "minimum delay before application")
+
+	sporeCosign = sporeSignCmd.Flags().String("cosign", "", "cosign the spore with this identity instead of signing it as its emitter")
+
+	sporeAddr = sporeSubmitCmd.Flags().StringP("server", "s", "localhost:4200", "server address")
+	sporeConnTimeout = sporeSubmitCmd.Flags().DurationP("timeout", "t", 10*time.Second, "connection timeout")
+
+	sporeCmd.AddCommand(
+		sporeBuildCmd,
+		sporeSignCmd,
+		sporeSubmitCmd,
+	)
+	RootCmd.AddCommand(sporeCmd)
+}
+
+// parseOperation parses an operation written as "OP key data".
+func parseOperation(raw string) (*db.Operation, error) {
+	args := strings.SplitN(raw, " ", 3)
+	if len(args) < 3 {
+		return nil, fmt.Errorf("invalid operation %q, expecting \"OP key data\"", raw)
+	}
+
+	op, ok := db.Operation_Op_value[strings.ToUpper(args[0])]
+	if !ok {
+		return nil, fmt.Errorf("unknown operation %q", args[0])
+	}
+
+	return &db.Operation{
+		Key:  args[1],
+		Op:   db.Operation_Op(op),
+		Data: []byte(args[2]),
+	}, nil
+}
+
+func readSpore(p string) (*db.Spore, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	u := &jsonpb.Unmarshaler{}
+	s := &db.Spore{}
+	return s, u.Unmarshal(f, s)
+}
+
+func writeSpore(s *db.Spore) {
+	m := &jsonpb.Marshaler{Indent: "  ", OrigName: true}
+	raw, err := m.MarshalToString(s)
+	check(err)
+	fmt.Println(raw)
+}
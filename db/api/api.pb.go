@@ -15,6 +15,15 @@ It has these top-level messages:
 	Boolean
 	Transaction
 	Receipt
+	Empty
+	PolicyList
+	UsageRequest
+	EmitterUsage
+	EvidenceList
+	Simulation
+	Check
+	PolicyUsage
+	ErrorDetail
 */
 package api
 
@@ -23,6 +32,7 @@ import fmt "fmt"
 import math "math"
 import db "gitlab.com/SporeDB/sporedb/db"
 import version "gitlab.com/SporeDB/sporedb/db/version"
+import google_protobuf "github.com/golang/protobuf/ptypes/duration"
 
 import (
 	context "golang.org/x/net/context"
@@ -145,9 +155,14 @@ func (m *Boolean) GetBoolean() bool {
 }
 
 type Transaction struct {
-	Policy       string                `protobuf:"bytes,1,opt,name=policy" json:"policy,omitempty"`
-	Requirements map[string]*version.V `protobuf:"bytes,2,rep,name=requirements" json:"requirements,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
-	Operations   []*db.Operation       `protobuf:"bytes,3,rep,name=operations" json:"operations,omitempty"`
+	Policy       string                    `protobuf:"bytes,1,opt,name=policy" json:"policy,omitempty"`
+	Requirements map[string]*version.V     `protobuf:"bytes,2,rep,name=requirements" json:"requirements,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Operations   []*db.Operation           `protobuf:"bytes,3,rep,name=operations" json:"operations,omitempty"`
+	Timeout      *google_protobuf.Duration `protobuf:"bytes,4,opt,name=timeout" json:"timeout,omitempty"`
+	Dependencies []string                  `protobuf:"bytes,5,rep,name=dependencies" json:"dependencies,omitempty"`
+	Delay        *google_protobuf.Duration `protobuf:"bytes,6,opt,name=delay" json:"delay,omitempty"`
+	Policies     []string                  `protobuf:"bytes,7,rep,name=policies" json:"policies,omitempty"`
+	Uuid         string                    `protobuf:"bytes,8,opt,name=uuid" json:"uuid,omitempty"`
 }
 
 func (m *Transaction) Reset()                    { *m = Transaction{} }
@@ -176,8 +191,44 @@ func (m *Transaction) GetOperations() []*db.Operation {
 	return nil
 }
 
+func (m *Transaction) GetTimeout() *google_protobuf.Duration {
+	if m != nil {
+		return m.Timeout
+	}
+	return nil
+}
+
+func (m *Transaction) GetDependencies() []string {
+	if m != nil {
+		return m.Dependencies
+	}
+	return nil
+}
+
+func (m *Transaction) GetDelay() *google_protobuf.Duration {
+	if m != nil {
+		return m.Delay
+	}
+	return nil
+}
+
+func (m *Transaction) GetPolicies() []string {
+	if m != nil {
+		return m.Policies
+	}
+	return nil
+}
+
+func (m *Transaction) GetUuid() string {
+	if m != nil {
+		return m.Uuid
+	}
+	return ""
+}
+
 type Receipt struct {
-	Uuid string `protobuf:"bytes,1,opt,name=uuid" json:"uuid,omitempty"`
+	Uuid   string         `protobuf:"bytes,1,opt,name=uuid" json:"uuid,omitempty"`
+	Status db.SporeStatus `protobuf:"varint,2,opt,name=status,enum=db.SporeStatus" json:"status,omitempty"`
 }
 
 func (m *Receipt) Reset()                    { *m = Receipt{} }
@@ -192,6 +243,274 @@ func (m *Receipt) GetUuid() string {
 	return ""
 }
 
+func (m *Receipt) GetStatus() db.SporeStatus {
+	if m != nil {
+		return m.Status
+	}
+	return db.SporeStatus_UNKNOWN
+}
+
+type Empty struct {
+}
+
+func (m *Empty) Reset()                    { *m = Empty{} }
+func (m *Empty) String() string            { return proto.CompactTextString(m) }
+func (*Empty) ProtoMessage()               {}
+func (*Empty) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{7} }
+
+type PolicyList struct {
+	Uuids []string `protobuf:"bytes,1,rep,name=uuids" json:"uuids,omitempty"`
+}
+
+func (m *PolicyList) Reset()                    { *m = PolicyList{} }
+func (m *PolicyList) String() string            { return proto.CompactTextString(m) }
+func (*PolicyList) ProtoMessage()               {}
+func (*PolicyList) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{8} }
+
+func (m *PolicyList) GetUuids() []string {
+	if m != nil {
+		return m.Uuids
+	}
+	return nil
+}
+
+type UsageRequest struct {
+	Policy  string `protobuf:"bytes,1,opt,name=policy" json:"policy,omitempty"`
+	Emitter string `protobuf:"bytes,2,opt,name=emitter" json:"emitter,omitempty"`
+}
+
+func (m *UsageRequest) Reset()                    { *m = UsageRequest{} }
+func (m *UsageRequest) String() string            { return proto.CompactTextString(m) }
+func (*UsageRequest) ProtoMessage()               {}
+func (*UsageRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{9} }
+
+func (m *UsageRequest) GetPolicy() string {
+	if m != nil {
+		return m.Policy
+	}
+	return ""
+}
+
+func (m *UsageRequest) GetEmitter() string {
+	if m != nil {
+		return m.Emitter
+	}
+	return ""
+}
+
+type EmitterUsage struct {
+	Size   uint64 `protobuf:"varint,1,opt,name=size" json:"size,omitempty"`
+	Spores uint64 `protobuf:"varint,2,opt,name=spores" json:"spores,omitempty"`
+	Staged uint64 `protobuf:"varint,3,opt,name=staged" json:"staged,omitempty"`
+}
+
+func (m *EmitterUsage) Reset()                    { *m = EmitterUsage{} }
+func (m *EmitterUsage) String() string            { return proto.CompactTextString(m) }
+func (*EmitterUsage) ProtoMessage()               {}
+func (*EmitterUsage) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{10} }
+
+func (m *EmitterUsage) GetSize() uint64 {
+	if m != nil {
+		return m.Size
+	}
+	return 0
+}
+
+func (m *EmitterUsage) GetSpores() uint64 {
+	if m != nil {
+		return m.Spores
+	}
+	return 0
+}
+
+func (m *EmitterUsage) GetStaged() uint64 {
+	if m != nil {
+		return m.Staged
+	}
+	return 0
+}
+
+type EvidenceList struct {
+	Evidences []*db.Evidence `protobuf:"bytes,1,rep,name=evidences" json:"evidences,omitempty"`
+}
+
+func (m *EvidenceList) Reset()                    { *m = EvidenceList{} }
+func (m *EvidenceList) String() string            { return proto.CompactTextString(m) }
+func (*EvidenceList) ProtoMessage()               {}
+func (*EvidenceList) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{11} }
+
+func (m *EvidenceList) GetEvidences() []*db.Evidence {
+	if m != nil {
+		return m.Evidences
+	}
+	return nil
+}
+
+// Simulation explains whether a transaction would be endorsed by the node.
+type Simulation struct {
+	Uuid     string            `protobuf:"bytes,1,opt,name=uuid" json:"uuid,omitempty"`
+	Checks   []*Check          `protobuf:"bytes,2,rep,name=checks" json:"checks,omitempty"`
+	Values   map[string][]byte `protobuf:"bytes,3,rep,name=values" json:"values,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Usages   []*PolicyUsage    `protobuf:"bytes,4,rep,name=usages" json:"usages,omitempty"`
+	Conflict string            `protobuf:"bytes,5,opt,name=conflict" json:"conflict,omitempty"`
+	Error    string            `protobuf:"bytes,6,opt,name=error" json:"error,omitempty"`
+}
+
+func (m *Simulation) Reset()                    { *m = Simulation{} }
+func (m *Simulation) String() string            { return proto.CompactTextString(m) }
+func (*Simulation) ProtoMessage()               {}
+func (*Simulation) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{12} }
+
+func (m *Simulation) GetUuid() string {
+	if m != nil {
+		return m.Uuid
+	}
+	return ""
+}
+
+func (m *Simulation) GetChecks() []*Check {
+	if m != nil {
+		return m.Checks
+	}
+	return nil
+}
+
+func (m *Simulation) GetValues() map[string][]byte {
+	if m != nil {
+		return m.Values
+	}
+	return nil
+}
+
+func (m *Simulation) GetUsages() []*PolicyUsage {
+	if m != nil {
+		return m.Usages
+	}
+	return nil
+}
+
+func (m *Simulation) GetConflict() string {
+	if m != nil {
+		return m.Conflict
+	}
+	return ""
+}
+
+func (m *Simulation) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+type Check struct {
+	Name   string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	Passed bool   `protobuf:"varint,2,opt,name=passed" json:"passed,omitempty"`
+	Error  string `protobuf:"bytes,3,opt,name=error" json:"error,omitempty"`
+}
+
+func (m *Check) Reset()                    { *m = Check{} }
+func (m *Check) String() string            { return proto.CompactTextString(m) }
+func (*Check) ProtoMessage()               {}
+func (*Check) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{13} }
+
+func (m *Check) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Check) GetPassed() bool {
+	if m != nil {
+		return m.Passed
+	}
+	return false
+}
+
+func (m *Check) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+// PolicyUsage is the resource usage of a policy and of the emitter under
+// this policy, before and after a simulated transaction.
+type PolicyUsage struct {
+	Policy         string        `protobuf:"bytes,1,opt,name=policy" json:"policy,omitempty"`
+	Size           uint64        `protobuf:"varint,2,opt,name=size" json:"size,omitempty"`
+	NewSize        uint64        `protobuf:"varint,3,opt,name=new_size,json=newSize" json:"new_size,omitempty"`
+	MaxSize        uint64        `protobuf:"varint,4,opt,name=max_size,json=maxSize" json:"max_size,omitempty"`
+	Emitter        *EmitterUsage `protobuf:"bytes,5,opt,name=emitter" json:"emitter,omitempty"`
+	EmitterNewSize uint64        `protobuf:"varint,6,opt,name=emitter_new_size,json=emitterNewSize" json:"emitter_new_size,omitempty"`
+}
+
+func (m *PolicyUsage) Reset()                    { *m = PolicyUsage{} }
+func (m *PolicyUsage) String() string            { return proto.CompactTextString(m) }
+func (*PolicyUsage) ProtoMessage()               {}
+func (*PolicyUsage) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{14} }
+
+func (m *PolicyUsage) GetPolicy() string {
+	if m != nil {
+		return m.Policy
+	}
+	return ""
+}
+
+func (m *PolicyUsage) GetSize() uint64 {
+	if m != nil {
+		return m.Size
+	}
+	return 0
+}
+
+func (m *PolicyUsage) GetNewSize() uint64 {
+	if m != nil {
+		return m.NewSize
+	}
+	return 0
+}
+
+func (m *PolicyUsage) GetMaxSize() uint64 {
+	if m != nil {
+		return m.MaxSize
+	}
+	return 0
+}
+
+func (m *PolicyUsage) GetEmitter() *EmitterUsage {
+	if m != nil {
+		return m.Emitter
+	}
+	return nil
+}
+
+func (m *PolicyUsage) GetEmitterNewSize() uint64 {
+	if m != nil {
+		return m.EmitterNewSize
+	}
+	return 0
+}
+
+// ErrorDetail is attached to the status of failed calls, to identify the
+// database error in a machine-readable way.
+type ErrorDetail struct {
+	Reason string `protobuf:"bytes,1,opt,name=reason" json:"reason,omitempty"`
+}
+
+func (m *ErrorDetail) Reset()                    { *m = ErrorDetail{} }
+func (m *ErrorDetail) String() string            { return proto.CompactTextString(m) }
+func (*ErrorDetail) ProtoMessage()               {}
+func (*ErrorDetail) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{15} }
+
+func (m *ErrorDetail) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterType((*Key)(nil), "api.Key")
 	proto.RegisterType((*Value)(nil), "api.Value")
@@ -200,6 +519,15 @@ func init() {
 	proto.RegisterType((*Boolean)(nil), "api.Boolean")
 	proto.RegisterType((*Transaction)(nil), "api.Transaction")
 	proto.RegisterType((*Receipt)(nil), "api.Receipt")
+	proto.RegisterType((*Empty)(nil), "api.Empty")
+	proto.RegisterType((*PolicyList)(nil), "api.PolicyList")
+	proto.RegisterType((*UsageRequest)(nil), "api.UsageRequest")
+	proto.RegisterType((*EmitterUsage)(nil), "api.EmitterUsage")
+	proto.RegisterType((*EvidenceList)(nil), "api.EvidenceList")
+	proto.RegisterType((*Simulation)(nil), "api.Simulation")
+	proto.RegisterType((*Check)(nil), "api.Check")
+	proto.RegisterType((*PolicyUsage)(nil), "api.PolicyUsage")
+	proto.RegisterType((*ErrorDetail)(nil), "api.ErrorDetail")
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -217,6 +545,17 @@ type SporeDBClient interface {
 	Members(ctx context.Context, in *Key, opts ...grpc.CallOption) (*Values, error)
 	Contains(ctx context.Context, in *KeyValue, opts ...grpc.CallOption) (*Boolean, error)
 	Submit(ctx context.Context, in *Transaction, opts ...grpc.CallOption) (*Receipt, error)
+	// Administration
+	Reload(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*PolicyList, error)
+	Usage(ctx context.Context, in *UsageRequest, opts ...grpc.CallOption) (*EmitterUsage, error)
+	Evidences(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*EvidenceList, error)
+	Cancel(ctx context.Context, in *Receipt, opts ...grpc.CallOption) (*Empty, error)
+	Prepare(ctx context.Context, in *Transaction, opts ...grpc.CallOption) (*db.Spore, error)
+	Cosign(ctx context.Context, in *db.Spore, opts ...grpc.CallOption) (*db.Spore, error)
+	Propose(ctx context.Context, in *db.Spore, opts ...grpc.CallOption) (*Receipt, error)
+	SubmitSpore(ctx context.Context, in *db.Spore, opts ...grpc.CallOption) (*Receipt, error)
+	Status(ctx context.Context, in *Receipt, opts ...grpc.CallOption) (*Receipt, error)
+	Simulate(ctx context.Context, in *Transaction, opts ...grpc.CallOption) (*Simulation, error)
 }
 
 type sporeDBClient struct {
@@ -263,6 +602,96 @@ func (c *sporeDBClient) Submit(ctx context.Context, in *Transaction, opts ...grp
 	return out, nil
 }
 
+func (c *sporeDBClient) Reload(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*PolicyList, error) {
+	out := new(PolicyList)
+	err := grpc.Invoke(ctx, "/api.SporeDB/Reload", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sporeDBClient) Usage(ctx context.Context, in *UsageRequest, opts ...grpc.CallOption) (*EmitterUsage, error) {
+	out := new(EmitterUsage)
+	err := grpc.Invoke(ctx, "/api.SporeDB/Usage", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sporeDBClient) Evidences(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*EvidenceList, error) {
+	out := new(EvidenceList)
+	err := grpc.Invoke(ctx, "/api.SporeDB/Evidences", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sporeDBClient) Cancel(ctx context.Context, in *Receipt, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := grpc.Invoke(ctx, "/api.SporeDB/Cancel", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sporeDBClient) Prepare(ctx context.Context, in *Transaction, opts ...grpc.CallOption) (*db.Spore, error) {
+	out := new(db.Spore)
+	err := grpc.Invoke(ctx, "/api.SporeDB/Prepare", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sporeDBClient) Cosign(ctx context.Context, in *db.Spore, opts ...grpc.CallOption) (*db.Spore, error) {
+	out := new(db.Spore)
+	err := grpc.Invoke(ctx, "/api.SporeDB/Cosign", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sporeDBClient) Propose(ctx context.Context, in *db.Spore, opts ...grpc.CallOption) (*Receipt, error) {
+	out := new(Receipt)
+	err := grpc.Invoke(ctx, "/api.SporeDB/Propose", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sporeDBClient) SubmitSpore(ctx context.Context, in *db.Spore, opts ...grpc.CallOption) (*Receipt, error) {
+	out := new(Receipt)
+	err := grpc.Invoke(ctx, "/api.SporeDB/SubmitSpore", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sporeDBClient) Status(ctx context.Context, in *Receipt, opts ...grpc.CallOption) (*Receipt, error) {
+	out := new(Receipt)
+	err := grpc.Invoke(ctx, "/api.SporeDB/Status", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sporeDBClient) Simulate(ctx context.Context, in *Transaction, opts ...grpc.CallOption) (*Simulation, error) {
+	out := new(Simulation)
+	err := grpc.Invoke(ctx, "/api.SporeDB/Simulate", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // Server API for SporeDB service
 
 type SporeDBServer interface {
@@ -270,6 +699,17 @@ type SporeDBServer interface {
 	Members(context.Context, *Key) (*Values, error)
 	Contains(context.Context, *KeyValue) (*Boolean, error)
 	Submit(context.Context, *Transaction) (*Receipt, error)
+	// Administration
+	Reload(context.Context, *Empty) (*PolicyList, error)
+	Usage(context.Context, *UsageRequest) (*EmitterUsage, error)
+	Evidences(context.Context, *Empty) (*EvidenceList, error)
+	Cancel(context.Context, *Receipt) (*Empty, error)
+	Prepare(context.Context, *Transaction) (*db.Spore, error)
+	Cosign(context.Context, *db.Spore) (*db.Spore, error)
+	Propose(context.Context, *db.Spore) (*Receipt, error)
+	SubmitSpore(context.Context, *db.Spore) (*Receipt, error)
+	Status(context.Context, *Receipt) (*Receipt, error)
+	Simulate(context.Context, *Transaction) (*Simulation, error)
 }
 
 func RegisterSporeDBServer(s *grpc.Server, srv SporeDBServer) {
@@ -348,6 +788,186 @@ func _SporeDB_Submit_Handler(srv interface{}, ctx context.Context, dec func(inte
 	return interceptor(ctx, in, info, handler)
 }
 
+func _SporeDB_Reload_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SporeDBServer).Reload(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.SporeDB/Reload",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SporeDBServer).Reload(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SporeDB_Usage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UsageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SporeDBServer).Usage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.SporeDB/Usage",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SporeDBServer).Usage(ctx, req.(*UsageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SporeDB_Evidences_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SporeDBServer).Evidences(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.SporeDB/Evidences",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SporeDBServer).Evidences(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SporeDB_Cancel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Receipt)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SporeDBServer).Cancel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.SporeDB/Cancel",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SporeDBServer).Cancel(ctx, req.(*Receipt))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SporeDB_Prepare_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Transaction)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SporeDBServer).Prepare(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.SporeDB/Prepare",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SporeDBServer).Prepare(ctx, req.(*Transaction))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SporeDB_Cosign_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(db.Spore)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SporeDBServer).Cosign(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.SporeDB/Cosign",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SporeDBServer).Cosign(ctx, req.(*db.Spore))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SporeDB_Propose_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(db.Spore)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SporeDBServer).Propose(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.SporeDB/Propose",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SporeDBServer).Propose(ctx, req.(*db.Spore))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SporeDB_SubmitSpore_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(db.Spore)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SporeDBServer).SubmitSpore(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.SporeDB/SubmitSpore",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SporeDBServer).SubmitSpore(ctx, req.(*db.Spore))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SporeDB_Status_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Receipt)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SporeDBServer).Status(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.SporeDB/Status",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SporeDBServer).Status(ctx, req.(*Receipt))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SporeDB_Simulate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Transaction)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SporeDBServer).Simulate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.SporeDB/Simulate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SporeDBServer).Simulate(ctx, req.(*Transaction))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _SporeDB_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "api.SporeDB",
 	HandlerType: (*SporeDBServer)(nil),
@@ -368,6 +988,46 @@ var _SporeDB_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Submit",
 			Handler:    _SporeDB_Submit_Handler,
 		},
+		{
+			MethodName: "Reload",
+			Handler:    _SporeDB_Reload_Handler,
+		},
+		{
+			MethodName: "Usage",
+			Handler:    _SporeDB_Usage_Handler,
+		},
+		{
+			MethodName: "Evidences",
+			Handler:    _SporeDB_Evidences_Handler,
+		},
+		{
+			MethodName: "Cancel",
+			Handler:    _SporeDB_Cancel_Handler,
+		},
+		{
+			MethodName: "Prepare",
+			Handler:    _SporeDB_Prepare_Handler,
+		},
+		{
+			MethodName: "Cosign",
+			Handler:    _SporeDB_Cosign_Handler,
+		},
+		{
+			MethodName: "Propose",
+			Handler:    _SporeDB_Propose_Handler,
+		},
+		{
+			MethodName: "SubmitSpore",
+			Handler:    _SporeDB_SubmitSpore_Handler,
+		},
+		{
+			MethodName: "Status",
+			Handler:    _SporeDB_Status_Handler,
+		},
+		{
+			MethodName: "Simulate",
+			Handler:    _SporeDB_Simulate_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "db/api/api.proto",
@@ -376,31 +1036,66 @@ var _SporeDB_serviceDesc = grpc.ServiceDesc{
 func init() { proto.RegisterFile("db/api/api.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 404 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x52, 0xcd, 0x8e, 0xd3, 0x30,
-	0x10, 0x6e, 0x36, 0x6c, 0x53, 0xa6, 0x5d, 0x54, 0x46, 0x08, 0xa2, 0x48, 0x2b, 0x55, 0x86, 0xc3,
-	0x82, 0x44, 0x2a, 0x85, 0x0b, 0xe2, 0x46, 0xf9, 0x3b, 0x44, 0x08, 0xc9, 0x8b, 0xf6, 0x6e, 0x37,
-	0x73, 0xb0, 0x68, 0xe3, 0x60, 0x3b, 0x2b, 0xe5, 0x79, 0x78, 0x3a, 0xde, 0x02, 0xd9, 0x49, 0x20,
-	0x4b, 0xb9, 0x70, 0x88, 0x32, 0xe3, 0xef, 0x67, 0xec, 0xcf, 0x86, 0x75, 0x25, 0xb7, 0xa2, 0x51,
-	0xfe, 0xcb, 0x1b, 0xa3, 0x9d, 0xc6, 0x58, 0x34, 0x2a, 0x7b, 0x50, 0xc9, 0xad, 0x6d, 0xb4, 0xa1,
-	0x7e, 0x31, 0x4b, 0x2b, 0xb9, 0xbd, 0x25, 0x63, 0x95, 0xae, 0xc7, 0x7f, 0x8f, 0xb0, 0x27, 0x10,
-	0x97, 0xd4, 0xe1, 0x1a, 0xe2, 0x6f, 0xd4, 0xa5, 0xd1, 0x26, 0xba, 0xba, 0xcf, 0x7d, 0xc9, 0xde,
-	0xc2, 0xf9, 0x8d, 0x38, 0xb4, 0x84, 0xcf, 0x20, 0x19, 0x24, 0x01, 0x5e, 0x16, 0x90, 0x8f, 0x16,
-	0x37, 0x7c, 0x84, 0x10, 0xe1, 0x5e, 0x25, 0x9c, 0x48, 0xcf, 0x36, 0xd1, 0xd5, 0x8a, 0x87, 0x9a,
-	0x15, 0xb0, 0x28, 0xa9, 0xeb, 0x5d, 0x4e, 0x06, 0xe0, 0x23, 0x38, 0xbf, 0xf5, 0xd0, 0x20, 0xe9,
-	0x1b, 0xb6, 0x83, 0x79, 0x10, 0xd8, 0xff, 0x9e, 0x1b, 0xff, 0x9e, 0xfb, 0x14, 0x92, 0x9d, 0xd6,
-	0x07, 0x12, 0x35, 0xa6, 0x90, 0xc8, 0xbe, 0x0c, 0x26, 0x0b, 0x3e, 0xb6, 0xec, 0x67, 0x04, 0xcb,
-	0xaf, 0x46, 0xd4, 0x56, 0xec, 0x9d, 0x37, 0x7a, 0x0c, 0xf3, 0x46, 0x1f, 0xd4, 0x7e, 0xdc, 0xe3,
-	0xd0, 0xe1, 0x47, 0x58, 0x19, 0xfa, 0xde, 0x2a, 0x43, 0x47, 0xaa, 0x9d, 0x0d, 0x83, 0x96, 0x05,
-	0xcb, 0x7d, 0xe2, 0x13, 0x7d, 0xce, 0x27, 0xa4, 0x0f, 0xb5, 0x33, 0x1d, 0xbf, 0xa3, 0xc3, 0x97,
-	0x00, 0xba, 0x21, 0x23, 0x3c, 0xd9, 0xa6, 0x71, 0x70, 0xb9, 0xc8, 0x2b, 0x99, 0x7f, 0x19, 0x57,
-	0xf9, 0x84, 0x90, 0x95, 0xf0, 0xf0, 0xc4, 0xf1, 0x1f, 0x21, 0x6e, 0xa6, 0x21, 0xde, 0x8d, 0xa8,
-	0x07, 0xde, 0x9c, 0xbd, 0x8e, 0xd8, 0x25, 0x24, 0x9c, 0xf6, 0xa4, 0x1a, 0xe7, 0xf3, 0x6a, 0x5b,
-	0x55, 0x0d, 0x1e, 0xa1, 0x2e, 0x7e, 0x44, 0x90, 0x5c, 0xfb, 0xd7, 0xf2, 0x7e, 0x87, 0x97, 0x10,
-	0x7f, 0x22, 0x87, 0x8b, 0x70, 0xbe, 0x92, 0xba, 0x0c, 0x42, 0x15, 0xee, 0x84, 0xcd, 0x90, 0x41,
-	0xf2, 0x99, 0x8e, 0x92, 0x8c, 0x9d, 0x50, 0x96, 0x7f, 0x28, 0x96, 0xcd, 0xf0, 0x39, 0x2c, 0xde,
-	0xe9, 0xda, 0x09, 0x55, 0x5b, 0xbc, 0x18, 0x49, 0x01, 0xcd, 0x56, 0xa1, 0x1d, 0x2e, 0x87, 0xcd,
-	0xf0, 0x05, 0xcc, 0xaf, 0x5b, 0x79, 0x54, 0x0e, 0xd7, 0x7f, 0x07, 0x3a, 0x70, 0x87, 0x7d, 0xb3,
-	0x99, 0x9c, 0x87, 0x07, 0xfb, 0xea, 0x57, 0x00, 0x00, 0x00, 0xff, 0xff, 0xc4, 0x34, 0x14, 0x69,
-	0xf3, 0x02, 0x00, 0x00,
+	// 973 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x55, 0xdb, 0x8e, 0x1b, 0x45,
+	0x13, 0xb6, 0x77, 0xec, 0x19, 0xbb, 0xec, 0x6c, 0x36, 0xad, 0x5f, 0x3f, 0x13, 0x23, 0xd0, 0xaa,
+	0x59, 0x88, 0x09, 0x30, 0x96, 0xbc, 0x37, 0x90, 0x2b, 0xd8, 0x43, 0x10, 0x5a, 0x0e, 0x51, 0x1b,
+	0x72, 0x1b, 0xb5, 0x3d, 0x15, 0x33, 0xca, 0x9c, 0x98, 0xee, 0xd9, 0xc4, 0x3c, 0x05, 0xef, 0xc0,
+	0xbb, 0xe4, 0xb9, 0x50, 0x57, 0xf7, 0xd8, 0xe3, 0xdd, 0x0d, 0x87, 0x0b, 0xcb, 0x5d, 0x55, 0x5f,
+	0xd7, 0xf1, 0xeb, 0x1a, 0x38, 0x8a, 0x97, 0x33, 0x59, 0x26, 0xe6, 0x17, 0x95, 0x55, 0xa1, 0x0b,
+	0xe6, 0xc9, 0x32, 0x99, 0x1c, 0xc6, 0xcb, 0x99, 0x2a, 0x8b, 0x0a, 0xad, 0x72, 0x12, 0xc6, 0xcb,
+	0xd9, 0x35, 0x56, 0x2a, 0x29, 0xf2, 0xe6, 0xdf, 0x59, 0x3e, 0x5c, 0x17, 0xc5, 0x3a, 0xc5, 0x19,
+	0x49, 0xcb, 0xfa, 0xe5, 0x2c, 0xae, 0x2b, 0xa9, 0xb7, 0x76, 0xfe, 0x1e, 0x78, 0x57, 0xb8, 0x61,
+	0x47, 0xe0, 0xbd, 0xc2, 0x4d, 0xd8, 0x3d, 0xee, 0x4e, 0x87, 0xc2, 0x1c, 0xf9, 0x37, 0xd0, 0x7f,
+	0x2e, 0xd3, 0x1a, 0xd9, 0x09, 0x04, 0xce, 0x25, 0x99, 0x47, 0x73, 0x88, 0x9a, 0x10, 0xcf, 0x45,
+	0x63, 0x62, 0x0c, 0x7a, 0xb1, 0xd4, 0x32, 0x3c, 0x38, 0xee, 0x4e, 0xc7, 0x82, 0xce, 0x7c, 0x0e,
+	0x83, 0x2b, 0xdc, 0x58, 0x2f, 0xb7, 0x02, 0xb0, 0xff, 0x41, 0xff, 0xda, 0x98, 0xdc, 0x15, 0x2b,
+	0xf0, 0x33, 0xf0, 0xe9, 0x82, 0xfa, 0xcf, 0x71, 0xbd, 0x6d, 0xdc, 0x8f, 0x20, 0x38, 0x2b, 0x8a,
+	0x14, 0x65, 0xce, 0x42, 0x08, 0x96, 0xf6, 0x48, 0x4e, 0x06, 0xa2, 0x11, 0xf9, 0x9f, 0x1e, 0x8c,
+	0x7e, 0xae, 0x64, 0xae, 0xe4, 0xca, 0xb4, 0x83, 0xfd, 0x1f, 0xfc, 0xb2, 0x48, 0x93, 0x55, 0x93,
+	0xa3, 0x93, 0xd8, 0x53, 0x18, 0x57, 0xf8, 0x5b, 0x9d, 0x54, 0x98, 0x61, 0xae, 0x15, 0x05, 0x1a,
+	0xcd, 0x79, 0x64, 0x26, 0xd2, 0xba, 0x1f, 0x89, 0x16, 0xe8, 0x32, 0xd7, 0xd5, 0x46, 0xec, 0xdd,
+	0x63, 0x5f, 0x00, 0x14, 0x25, 0xda, 0xde, 0xab, 0xd0, 0x23, 0x2f, 0xf7, 0xa2, 0x78, 0x19, 0xfd,
+	0xd4, 0x68, 0x45, 0x0b, 0xc0, 0x4e, 0x21, 0xd0, 0x49, 0x86, 0x45, 0xad, 0xc3, 0x1e, 0x55, 0xff,
+	0x30, 0xb2, 0x93, 0x8c, 0x9a, 0x49, 0x46, 0x17, 0x6e, 0x92, 0xa2, 0x41, 0x32, 0x0e, 0xe3, 0x18,
+	0x4b, 0xcc, 0x63, 0xcc, 0x57, 0x09, 0xaa, 0xb0, 0x7f, 0xec, 0x4d, 0x87, 0x62, 0x4f, 0xc7, 0x66,
+	0xd0, 0x8f, 0x31, 0x95, 0x9b, 0xd0, 0xff, 0x27, 0xb7, 0x16, 0xc7, 0x26, 0x30, 0xa0, 0x56, 0x18,
+	0x87, 0x01, 0x39, 0xdc, 0xca, 0xa6, 0xfb, 0x75, 0x9d, 0xc4, 0xe1, 0x80, 0x5a, 0x46, 0xe7, 0xc9,
+	0x15, 0x3c, 0xb8, 0xd5, 0x8b, 0x3b, 0xc6, 0x7f, 0xdc, 0x1e, 0xff, 0xfe, 0x70, 0xad, 0xe1, 0xc9,
+	0xc1, 0x97, 0x5d, 0xfe, 0x14, 0x02, 0x81, 0x2b, 0x4c, 0x4a, 0xbd, 0x8d, 0xd5, 0xdd, 0xc5, 0x62,
+	0x8f, 0xc0, 0x57, 0x5a, 0xea, 0x5a, 0x91, 0x97, 0xc3, 0xf9, 0x7d, 0xd3, 0xd0, 0x85, 0x79, 0x18,
+	0x0b, 0x52, 0x0b, 0x67, 0xe6, 0x01, 0xf4, 0x2f, 0xb3, 0x52, 0x6f, 0x38, 0x07, 0x78, 0x46, 0x83,
+	0xfd, 0x3e, 0x51, 0xda, 0x70, 0xd0, 0xf8, 0x51, 0x61, 0x97, 0x0a, 0xb3, 0x02, 0xff, 0x1a, 0xc6,
+	0xbf, 0x28, 0xb9, 0x46, 0x53, 0x06, 0x2a, 0xfd, 0x4e, 0x6a, 0x84, 0x10, 0x60, 0x96, 0x68, 0x8d,
+	0x15, 0x85, 0x1f, 0x8a, 0x46, 0xe4, 0x02, 0xc6, 0x97, 0xf6, 0x48, 0x8e, 0x4c, 0xee, 0x2a, 0xf9,
+	0x1d, 0xe9, 0x7e, 0x4f, 0xd0, 0xd9, 0x78, 0xa5, 0x27, 0x6c, 0x73, 0xef, 0x09, 0x27, 0x91, 0x5e,
+	0xcb, 0x35, 0xc6, 0xa1, 0xe7, 0xf4, 0x24, 0xf1, 0x27, 0x30, 0xbe, 0xbc, 0x4e, 0xcc, 0x18, 0x91,
+	0x72, 0x7f, 0x0c, 0x43, 0x74, 0xb2, 0xcd, 0x7f, 0x34, 0x1f, 0x9b, 0xf2, 0x1b, 0x90, 0xd8, 0x99,
+	0xf9, 0x1f, 0x07, 0x00, 0x8b, 0x24, 0xab, 0x53, 0x9a, 0xec, 0x9d, 0xad, 0xe4, 0xe0, 0xaf, 0x7e,
+	0xc5, 0xd5, 0xab, 0x86, 0xe1, 0x40, 0x0c, 0x3f, 0x37, 0x2a, 0xe1, 0x2c, 0xec, 0x14, 0x7c, 0x1a,
+	0x4d, 0xc3, 0xdf, 0xf7, 0x09, 0xb3, 0x73, 0x1c, 0xd9, 0xa7, 0x6b, 0xe9, 0xef, 0xa0, 0x6c, 0x0a,
+	0x7e, 0x6d, 0x9a, 0xa0, 0xc2, 0x1e, 0x5d, 0x3a, 0xa2, 0x4b, 0x76, 0x08, 0xb6, 0xcd, 0xce, 0x6e,
+	0x98, 0xb6, 0x2a, 0xf2, 0x97, 0x69, 0xb2, 0xd2, 0x61, 0x9f, 0x52, 0xdb, 0xca, 0x66, 0x52, 0x58,
+	0x55, 0x45, 0x45, 0xb4, 0x1d, 0x0a, 0x2b, 0x4c, 0xbe, 0x82, 0x51, 0x2b, 0xe4, 0xbf, 0x5d, 0x32,
+	0xc4, 0xac, 0xef, 0xa0, 0x4f, 0xc5, 0x99, 0x66, 0xe4, 0x32, 0xc3, 0xa6, 0x19, 0xe6, 0x4c, 0x13,
+	0x97, 0x4a, 0x61, 0x4c, 0xf7, 0x06, 0xc2, 0x49, 0xbb, 0x2c, 0xbc, 0x56, 0x16, 0xfc, 0x6d, 0x17,
+	0x46, 0xad, 0x7a, 0xde, 0xc9, 0x97, 0x86, 0x05, 0x07, 0x2d, 0x16, 0x3c, 0x84, 0x41, 0x8e, 0xaf,
+	0x5f, 0x90, 0xde, 0xce, 0x3b, 0xc8, 0xf1, 0xf5, 0xc2, 0x99, 0x32, 0xf9, 0xc6, 0x9a, 0x7a, 0xd6,
+	0x94, 0xc9, 0x37, 0x64, 0xfa, 0x6c, 0xc7, 0xbc, 0x3e, 0x3d, 0x9f, 0x07, 0xd4, 0xd4, 0x36, 0xe7,
+	0xb6, 0x64, 0x64, 0x53, 0x38, 0x72, 0xc7, 0x17, 0xdb, 0x50, 0x3e, 0xf9, 0x3b, 0x74, 0xfa, 0x1f,
+	0x6d, 0x44, 0xfe, 0x31, 0x8c, 0x2e, 0x4d, 0x45, 0x17, 0xa8, 0x65, 0x92, 0x9a, 0x3a, 0x2a, 0x94,
+	0xca, 0x2d, 0xe0, 0xa1, 0x70, 0xd2, 0xfc, 0x6d, 0x0f, 0x02, 0x7a, 0x64, 0x17, 0x67, 0xec, 0x03,
+	0xf0, 0xbe, 0x45, 0xcd, 0x06, 0x14, 0xff, 0x0a, 0x37, 0x13, 0xcb, 0x1b, 0x9a, 0x0a, 0xef, 0x30,
+	0x0e, 0xc1, 0x0f, 0x98, 0x2d, 0xb1, 0x52, 0x2d, 0xc8, 0x68, 0x07, 0x51, 0xbc, 0xc3, 0x3e, 0x85,
+	0xc1, 0x79, 0x91, 0x6b, 0x99, 0xe4, 0x8a, 0xdd, 0x6b, 0x40, 0x64, 0x9d, 0x8c, 0x49, 0x74, 0xcb,
+	0x9c, 0x77, 0xd8, 0x63, 0xf0, 0x17, 0xf5, 0x32, 0x4b, 0x34, 0x3b, 0xba, 0xb9, 0x80, 0x1d, 0xd6,
+	0x6d, 0x0b, 0xde, 0x61, 0x53, 0x18, 0x59, 0x2c, 0xa5, 0xca, 0x86, 0xdb, 0xd5, 0x70, 0x0b, 0x79,
+	0x02, 0xfe, 0xb9, 0xcc, 0x57, 0x98, 0xb2, 0x3d, 0x8b, 0x2b, 0xc5, 0xee, 0x8d, 0x0e, 0xfb, 0x04,
+	0x7c, 0xbb, 0x54, 0x6e, 0xa0, 0x6e, 0x7a, 0x9b, 0xc1, 0xc0, 0xbd, 0x08, 0xbc, 0x23, 0xcb, 0xfb,
+	0x37, 0x9e, 0x0c, 0x25, 0x1a, 0x3c, 0xab, 0xb0, 0x94, 0xd5, 0x5d, 0xf8, 0x5d, 0xda, 0xbc, 0xc3,
+	0x8e, 0xc1, 0x3f, 0x2f, 0x54, 0xb2, 0xce, 0xdb, 0xd5, 0xec, 0x21, 0x4e, 0x8c, 0xaf, 0xa2, 0x2c,
+	0xd4, 0xdf, 0x16, 0xfc, 0x08, 0x7c, 0x81, 0x69, 0x21, 0x63, 0xd6, 0x2a, 0xd1, 0xa5, 0xb6, 0xdb,
+	0x8e, 0x54, 0x4b, 0xdf, 0x52, 0xda, 0xf2, 0xab, 0xbd, 0x15, 0x27, 0xb7, 0x29, 0xc7, 0x3b, 0xec,
+	0x73, 0x18, 0x36, 0xfb, 0x47, 0xed, 0x39, 0x77, 0xe8, 0xd6, 0x02, 0xe3, 0x9d, 0xa5, 0x4f, 0x1f,
+	0x9d, 0xd3, 0xbf, 0x06, 0x00, 0xb8, 0xd1, 0x7a, 0xcb, 0xe6, 0x08, 0x00, 0x00,
 }
@@ -0,0 +1,205 @@
+package db
+
+import (
+	"bytes"
+	"crypto/sha512"
+	"errors"
+
+	"go.uber.org/zap"
+)
+
+// The emitter of a spore may cancel it until its quorum is reached, with a
+// Cancel message signed with the key used to sign the spore. A cancelled spore
+// is dropped from the staging or waiting list and recorded as vetoed until its
+// deadline: our endorsement is withdrawn, and conflicting waiting spores are
+// re-evaluated. Nodes that already committed or applied the spore ignore the
+// cancellation, since the other nodes of the cluster may apply it anyway, but
+// still relay it once to the nodes which did not.
+//
+// Cancellations received before their spore are buffered like orphan
+// endorsements, and checked when the spore is endorsed.
+
+// Error messages for cancellations.
+var (
+	ErrForeignSpore     = errors.New("unable to cancel a spore emitted by another node")
+	ErrAlreadyCommitted = errors.New("unable to cancel a spore whose quorum is reached")
+	ErrDuplicatedCancel = errors.New("the cancellation of the committed spore is already relayed")
+)
+
+const maxOrphanCancels = 8 // per spore, forged ones included
+
+func cancelHash(sporeHash []byte) []byte {
+	hash := sha512.Sum512(append([]byte("cancel:"), sporeHash...))
+	return hash[:]
+}
+
+// Cancel cancels a pending spore emitted by this node, and broadcasts the cancellation.
+func (db *DB) Cancel(uuid string) error {
+	s := db.PendingSpore(uuid)
+	if s == nil {
+		return ErrNoRelatedSpore
+	}
+
+	if s.Emitter != db.Identity {
+		return ErrForeignSpore
+	}
+
+	signature, err := db.KeyRing.Sign(cancelHash(db.HashSpore(s)))
+	if err != nil {
+		return err
+	}
+
+	c := &Cancel{Uuid: uuid, Signature: signature}
+	err = db.AddCancel(c)
+	if err == nil || err == ErrAlreadyCommitted {
+		db.Messages <- c
+	}
+	return err
+}
+
+// AddCancel registers the incoming cancellation, dropping the related pending spore.
+// Cancellations of unknown spores are buffered, and ErrNoRelatedSpore is returned.
+// ErrAlreadyCommitted is returned for the first valid cancellation of a committed
+// spore, which shall be relayed nonetheless.
+func (db *DB) AddCancel(c *Cancel) error {
+	err := db.addCancel(c)
+	if err == ErrNoRelatedSpore && !db.isDone(c.Uuid) {
+		db.addOrphanCancel(c)
+	}
+	return err
+}
+
+// addCancel registers the cancellation of a pending spore.
+func (db *DB) addCancel(c *Cancel) error {
+	db.waitingMutex.Lock()
+	db.stagingMutex.Lock()
+
+	trigger, staged := db.staging[c.Uuid]
+	if !staged {
+		trigger = db.waiting[c.Uuid]
+	}
+
+	if trigger == nil {
+		db.stagingMutex.Unlock()
+		db.waitingMutex.Unlock()
+		return ErrNoRelatedSpore
+	}
+
+	if err := db.verifyCancel(c, trigger.spore); err != nil {
+		db.stagingMutex.Unlock()
+		db.waitingMutex.Unlock()
+		return err
+	}
+
+	if staged && trigger.committed {
+		cancelled := trigger.cancelled
+		trigger.cancelled = true
+		db.stagingMutex.Unlock()
+		db.waitingMutex.Unlock()
+		if cancelled {
+			return ErrDuplicatedCancel
+		}
+		return ErrAlreadyCommitted
+	}
+
+	if staged {
+		trigger.timer.Stop()
+		db.removeStaging(c.Uuid)
+	} else {
+		db.removeWaiting(c.Uuid)
+	}
+
+	db.stagingMutex.Unlock()
+	db.waitingMutex.Unlock()
+
+	zap.L().Info("Cancel",
+		zap.String("uuid", c.Uuid),
+	)
+
+	db.veto(trigger)
+	go db.release(trigger.spore)
+	return nil
+}
+
+// verifyCancel checks the signature of the cancellation by the emitter of the spore.
+func (db *DB) verifyCancel(c *Cancel, s *Spore) error {
+	emitter := s.Emitter
+	if emitter == db.Identity {
+		emitter = ""
+	}
+
+	err := db.KeyRing.Verify(emitter, cancelHash(db.HashSpore(s)), c.Signature)
+	if err != nil {
+		zap.L().Warn("Invalid cancellation",
+			zap.String("uuid", c.Uuid),
+			zap.String("emitter", s.Emitter),
+			zap.Error(err),
+		)
+	}
+	return err
+}
+
+// addOrphanCancel buffers the cancellation of an unknown spore.
+// It is thread-safe.
+func (db *DB) addOrphanCancel(c *Cancel) {
+	db.orphansMutex.Lock()
+	var cancels []*Cancel
+	if raw, ok := db.cancels.Get(c.Uuid); ok {
+		cancels = raw.([]*Cancel)
+	}
+
+	duplicated := false
+	for _, c2 := range cancels {
+		duplicated = duplicated || bytes.Equal(c2.Signature, c.Signature)
+	}
+
+	if !duplicated && len(cancels) < maxOrphanCancels {
+		db.cancels.Add(c.Uuid, append(cancels, c))
+	}
+	db.orphansMutex.Unlock()
+
+	// The spore may have been endorsed meanwhile, missing this cancellation
+	if db.PendingSpore(c.Uuid) != nil {
+		for _, c := range db.takeCancels(c.Uuid) {
+			if err := db.addCancel(c); err == nil || err == ErrAlreadyCommitted {
+				db.Messages <- c
+			}
+		}
+	}
+}
+
+// takeCancels removes and returns the buffered cancellations of the spore.
+// It is thread-safe.
+func (db *DB) takeCancels(uuid string) []*Cancel {
+	db.orphansMutex.Lock()
+	defer db.orphansMutex.Unlock()
+
+	raw, ok := db.cancels.Get(uuid)
+	if !ok {
+		return nil
+	}
+
+	db.cancels.Remove(uuid)
+	return raw.([]*Cancel)
+}
+
+// cancelled returns true if a buffered cancellation of the spore is valid,
+// in which case the spore is vetoed before its endorsement, and the
+// cancellation relayed.
+func (db *DB) cancelled(s *Spore) bool {
+	for _, c := range db.takeCancels(s.Uuid) {
+		if db.verifyCancel(c, s) != nil {
+			continue
+		}
+
+		zap.L().Info("Cancel",
+			zap.String("uuid", s.Uuid),
+		)
+
+		db.veto(&dbTrigger{spore: s})
+		go db.release(s)
+		db.Messages <- c
+		return true
+	}
+	return false
+}
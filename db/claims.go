@@ -0,0 +1,95 @@
+package db
+
+import (
+	"errors"
+	"strings"
+)
+
+// Policies may claim key prefixes: claimed keys can only be modified by
+// spores of the owning policy, whatever the specifications of other policies.
+// Claims of two different policies must not overlap, so that every claimed
+// key belongs to exactly one policy. An unclaimed key belongs to the policy
+// whose specifications match it, if it is the only one: keys matched by the
+// specifications of several policies must be claimed to be modified.
+
+// Error messages for claims.
+var (
+	ErrClaimOverlap  = errors.New("the policy claims keys already claimed by another policy")
+	ErrOpClaimedKey  = errors.New("the requested key is claimed by another policy")
+	ErrOpSharedKey   = errors.New("the requested key is matched by several policies, and must be claimed by one of them")
+	ErrInvalidClaims = errors.New("the policy claims internal keys")
+)
+
+// checkClaims returns an error if the claims of the policy overlap with
+// the claims of another policy.
+//
+// It must be called with policiesMutex locked for registered policies.
+func checkClaims(policies map[string]*Policy, p *Policy) error {
+	for _, c := range p.Claims {
+		if strings.HasPrefix(c, InternalKeyPrefix) || strings.HasPrefix(InternalKeyPrefix, c) {
+			return ErrInvalidClaims
+		}
+	}
+
+	for uuid, other := range policies {
+		if uuid == p.Uuid {
+			continue
+		}
+
+		for _, c := range p.Claims {
+			for _, c2 := range other.Claims {
+				if strings.HasPrefix(c, c2) || strings.HasPrefix(c2, c) {
+					return ErrClaimOverlap
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkOwner returns an error if the key does not belong to the policy:
+// either claimed by another policy, or unclaimed and matched by the
+// specifications of the policy and of other policies.
+// Keys not matched by the policy are left to its specifications check.
+func (db *DB) checkOwner(policy, key string) error {
+	db.policiesMutex.RLock()
+	defer db.policiesMutex.RUnlock()
+
+	for uuid, p := range db.policies {
+		for _, c := range p.Claims {
+			if strings.HasPrefix(key, c) {
+				if uuid != policy {
+					return ErrOpClaimedKey
+				}
+				return nil
+			}
+		}
+	}
+
+	if !db.policiesComp[policy].matches(key) {
+		return nil
+	}
+
+	for uuid, c := range db.policiesComp {
+		if uuid != policy && c.matches(key) {
+			return ErrOpSharedKey
+		}
+	}
+
+	return nil
+}
+
+// matches returns true if one of the specifications matches the key.
+func (c *compiledPolicy) matches(key string) bool {
+	if c == nil {
+		return false
+	}
+
+	for _, r := range c.regexes {
+		if r.MatchString(key) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,87 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"google.golang.org/grpc"
+
+	"gitlab.com/SporeDB/sporedb/db"
+	"gitlab.com/SporeDB/sporedb/db/api"
+)
+
+// Reload asks the endpoint to reload its policies and keyring.
+// It returns the policies registered after the reload.
+func (c *Client) Reload(ctx context.Context) (policies []string, err error) {
+	res, err := c.client.Reload(ctx, &api.Empty{})
+	if res != nil {
+		policies = res.Uuids
+	}
+
+	return
+}
+
+func (c *Client) processRELOAD(arg string) {
+	ctx, done := c.ctx()
+	defer done()
+
+	policies, err := c.Reload(ctx)
+	if err != nil {
+		fmt.Println("Error:", grpc.ErrorDesc(err))
+		return
+	}
+
+	fmt.Println("Policies:", strings.Join(policies, ", "))
+}
+
+// Usage returns the resource usage of an emitter under a policy.
+func (c *Client) Usage(ctx context.Context, policy, emitter string) (*api.EmitterUsage, error) {
+	return c.client.Usage(ctx, &api.UsageRequest{Policy: policy, Emitter: emitter})
+}
+
+func (c *Client) processUSAGE(arg string) {
+	emitter, policy, err := split2args(arg)
+	if err != nil {
+		fmt.Println("USAGE function expects two arguments: (emitter, policy)")
+		return
+	}
+
+	ctx, done := c.ctx()
+	defer done()
+
+	usage, err := c.Usage(ctx, policy, emitter)
+	if err != nil {
+		fmt.Println("Error:", grpc.ErrorDesc(err))
+		return
+	}
+
+	fmt.Println("Stored bytes:", usage.Size)
+	fmt.Println("Spores in current window:", usage.Spores)
+	fmt.Println("Staged spores:", usage.Staged)
+}
+
+// Evidences returns the equivocation evidences stored by the endpoint.
+func (c *Client) Evidences(ctx context.Context) ([]*db.Evidence, error) {
+	res, err := c.client.Evidences(ctx, &api.Empty{})
+	if err != nil {
+		return nil, err
+	}
+
+	return res.Evidences, nil
+}
+
+func (c *Client) processEVIDENCES(arg string) {
+	ctx, done := c.ctx()
+	defer done()
+
+	evidences, err := c.Evidences(ctx)
+	if err != nil {
+		fmt.Println("Error:", grpc.ErrorDesc(err))
+		return
+	}
+
+	for _, e := range evidences {
+		fmt.Println(e.Emitter, "endorsed", e.First.Uuid, "and", e.Second.Uuid)
+	}
+}
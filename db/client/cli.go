@@ -1,6 +1,9 @@
 package client
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
 
 type cliMap map[string]func(arg string)
 
@@ -14,9 +17,16 @@ func (c *Client) getCLIMap() cliMap {
 		"MUL":       c.processGeneric2("MUL"),
 		"SADD":      c.processGeneric2("SADD"),
 		"SREM":      c.processGeneric2("SREM"),
+		"CANCEL":    c.processCANCEL,
+		"STATUS":    c.processSTATUS,
+		"SIMULATE":  c.processSIMULATE,
 		"SMEMBERS":  c.processMEMBERS,
 		"SCONTAINS": c.processCONTAINS,
 		"POL":       c.SetPolicy,
+		"TIMEOUT":   c.processTIMEOUT,
+		"RELOAD":    c.processRELOAD,
+		"USAGE":     c.processUSAGE,
+		"EVIDENCES": c.processEVIDENCES,
 	}
 }
 
@@ -25,3 +35,20 @@ func (c *Client) SetPolicy(pol string) {
 	c.policy = pol
 	fmt.Println("Now using policy", pol)
 }
+
+// SetSporeTimeout sets the spore timeout requested on submission.
+// The server default is used if zero, and the timeout is capped by the policy.
+func (c *Client) SetSporeTimeout(timeout time.Duration) {
+	c.timeout = timeout
+}
+
+func (c *Client) processTIMEOUT(arg string) {
+	timeout, err := time.ParseDuration(arg)
+	if err != nil {
+		fmt.Println("TIMEOUT function expects a duration, such as 10s")
+		return
+	}
+
+	c.SetSporeTimeout(timeout)
+	fmt.Println("Now requesting spore timeout", timeout)
+}
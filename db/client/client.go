@@ -1,7 +1,7 @@
 // Package client provides SporeDB API client.
 //
-// It can be used by external applications willing to communicate with a single SporeDB node.
-// The API client is able to get current state of one node's database, and submit transactions
+// It can be used by external applications willing to communicate with one or several SporeDB nodes.
+// The API client is able to get current state of the nodes' databases, and submit transactions
 // to the whole SporeDB cluster.
 //
 // Please note that the API is under development and is subject to change.
@@ -16,34 +16,43 @@ import (
 	"gitlab.com/SporeDB/sporedb/db/api"
 
 	"github.com/chzyer/readline"
-	"google.golang.org/grpc"
 )
 
 // Client is the GRPC SporeDB client.
 type Client struct {
 	Addr    string
 	Timeout time.Duration
-	conn    *grpc.ClientConn
-	client  api.SporeDBClient
+
+	Addrs          []string      // other endpoints, for load balancing and failover
+	HealthInterval time.Duration // zero for DefaultHealthInterval
+	ReadYourWrites bool          // prefer reading from endpoints which have seen the submitted spores
+
+	TxnRetries int           // retries of conflicting transactions, zero for DefaultTxnRetries
+	TxnBackoff time.Duration // initial backoff of retried transactions, zero for DefaultTxnBackoff
+
+	cluster
+	client  api.SporeDBClient // Addr endpoint
 	policy  string
+	timeout time.Duration // requested spore timeout, zero for the server default
 }
 
-// Connect proceeds to the GRPC connection step to the server.
-func (c *Client) Connect() (err error) {
-	c.conn, err = grpc.Dial(c.Addr, grpc.WithInsecure(), grpc.WithBlock(), grpc.WithTimeout(c.Timeout))
-	if err != nil {
-		return err
-	}
-
-	c.client = api.NewSporeDBClient(c.conn)
-	return nil
+// Connect proceeds to the GRPC connection step to the servers.
+// It succeeds if at least one of them is available.
+func (c *Client) Connect() error {
+	return c.dial()
 }
 
-// Close closes the GRPC connection to the server.
+// Close closes the GRPC connections to the servers.
 func (c *Client) Close() {
-	if c.conn != nil {
-		_ = c.conn.Close()
+	if c.stop != nil {
+		close(c.stop)
+		c.stop = nil
+	}
+
+	for _, e := range c.endpoints {
+		_ = e.conn.Close()
 	}
+	c.endpoints = nil
 }
 
 // CLI starts a command line interface to dial with the GRPC server (debug and maintenance).
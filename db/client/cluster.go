@@ -0,0 +1,356 @@
+package client
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+
+	"gitlab.com/SporeDB/sporedb/db"
+	"gitlab.com/SporeDB/sporedb/db/api"
+)
+
+// The client may be connected to several nodes of the cluster. Reads are
+// spread across the healthy endpoints, while submissions are sent to the
+// first healthy endpoint, Addr being preferred. Requests failing because of
+// an unavailable endpoint are retried on the other ones, until their context
+// is done. Requests about a spore, such as Status or Cancel, are sent to the
+// endpoint which accepted it. Requests about the node itself, such as Reload
+// or Cosign, are sent to Addr.
+//
+// Submitted transactions are turned into spores by the endpoint, so that two
+// endpoints would build two different spores with the same uuid. A failed
+// submission is thus only moved to another endpoint once the first one
+// reports not knowing the spore. Otherwise, it is to be retried on the same
+// endpoint, where it is idempotent thanks to its uuid.
+
+// DefaultHealthInterval is the default delay between two health checks of the endpoints.
+const DefaultHealthInterval = 5 * time.Second
+
+// Bounds of the spores tracked by the client.
+const (
+	maxTrackedRoutes = 1024
+	maxTrackedWrites = 64
+)
+
+// endpoint is one node of the cluster.
+type endpoint struct {
+	addr    string
+	conn    *grpc.ClientConn
+	client  api.SporeDBClient
+	healthy bool
+	seen    map[string]bool // tracked writes applied or dropped by the node
+}
+
+// cluster holds the state of the endpoints, and the spores tracked by the client.
+type cluster struct {
+	endpoints []*endpoint
+	mutex     sync.Mutex
+	next      int                  // next reading endpoint
+	routes    map[string]*endpoint // endpoints having accepted the spores
+	routedIDs []string             // routed spores, oldest first
+	writes    []string             // writes not yet seen by every endpoint, oldest first
+	stop      chan struct{}
+}
+
+// dial connects to every endpoint. It fails if none of them is available.
+func (c *Client) dial() error {
+	c.routes = make(map[string]*endpoint)
+	var lastErr error
+	healthy := false
+
+	for _, addr := range append([]string{c.Addr}, c.Addrs...) {
+		e := &endpoint{addr: addr, seen: make(map[string]bool)}
+		conn, err := c.dialEndpoint(addr, grpc.WithBlock(), grpc.WithTimeout(c.Timeout))
+		if err == nil {
+			e.healthy, healthy = true, true
+		} else {
+			lastErr = err
+			if conn, err = c.dialEndpoint(addr); err != nil { // connects in background
+				c.Close()
+				return err
+			}
+		}
+
+		e.conn = conn
+		e.client = api.NewSporeDBClient(e.conn)
+		c.endpoints = append(c.endpoints, e)
+	}
+
+	if !healthy {
+		c.Close()
+		return lastErr
+	}
+
+	c.client = c.endpoints[0].client
+	if len(c.endpoints) > 1 {
+		c.stop = make(chan struct{})
+		go c.healthCheck(c.endpoints, c.stop)
+	}
+	return nil
+}
+
+func (c *Client) dialEndpoint(addr string, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
+	opts = append(opts, grpc.WithInsecure(), grpc.WithUnaryInterceptor(errorInterceptor))
+	return grpc.Dial(addr, opts...)
+}
+
+// healthCheck periodically checks the availability of the endpoints, until stopped.
+func (c *Client) healthCheck(endpoints []*endpoint, stop chan struct{}) {
+	interval := c.HealthInterval
+	if interval == 0 {
+		interval = DefaultHealthInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+
+		for _, e := range endpoints {
+			ctx, done := context.WithTimeout(context.Background(), interval)
+			_, err := e.client.Status(ctx, &api.Receipt{})
+			done()
+			c.setHealthy(e, err == nil || !(unavailable(err) || code(err) == codes.DeadlineExceeded))
+		}
+	}
+}
+
+// unavailable returns true if the error is caused by an unreachable endpoint.
+// Deadlines are not counted, since the request may still be running.
+func unavailable(err error) bool {
+	return code(err) == codes.Unavailable
+}
+
+func (c *Client) setHealthy(e *endpoint, healthy bool) {
+	c.mutex.Lock()
+	e.healthy = healthy
+	c.mutex.Unlock()
+}
+
+// Endpoints returns the addresses of the healthy endpoints.
+func (c *Client) Endpoints() (addrs []string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for _, e := range c.endpoints {
+		if e.healthy {
+			addrs = append(addrs, e.addr)
+		}
+	}
+	return
+}
+
+// ordered returns the endpoints, the healthy ones first. The healthy
+// endpoints are rotated from the next reading one if rotate is set.
+func (c *Client) ordered(rotate bool) []*endpoint {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	n := len(c.endpoints)
+	start := 0
+	if rotate && n > 0 {
+		start = c.next % n
+		c.next++
+	}
+
+	var healthy, unhealthy []*endpoint
+	for i := 0; i < n; i++ {
+		e := c.endpoints[(start+i)%n]
+		if e.healthy {
+			healthy = append(healthy, e)
+		} else {
+			unhealthy = append(unhealthy, e)
+		}
+	}
+	return append(healthy, unhealthy...)
+}
+
+// failover runs f on the endpoints until one of them is available, or the context is done.
+// It returns the endpoint which served the request.
+func (c *Client) failover(ctx context.Context, endpoints []*endpoint, f func(api.SporeDBClient) error) (e *endpoint, err error) {
+	if len(endpoints) == 0 { // not connected through Connect
+		return nil, f(c.client)
+	}
+
+	for _, e = range endpoints {
+		if err = f(e.client); !unavailable(err) || ctx.Err() != nil {
+			return
+		}
+		c.setHealthy(e, false)
+	}
+	return
+}
+
+// read runs f on the next healthy endpoint, and on the other ones while unavailable.
+// With ReadYourWrites, the endpoints which have seen every tracked write are preferred.
+func (c *Client) read(ctx context.Context, f func(api.SporeDBClient) error) error {
+	endpoints := c.ordered(true)
+	if c.ReadYourWrites {
+		var fresh, stale []*endpoint
+		for _, e := range endpoints {
+			if c.caughtUp(ctx, e) {
+				fresh = append(fresh, e)
+			} else {
+				stale = append(stale, e)
+			}
+		}
+		endpoints = append(fresh, stale...)
+	}
+
+	_, err := c.failover(ctx, endpoints, f)
+	return err
+}
+
+// write runs the submission f on the endpoint which already received the spore,
+// or on the first healthy endpoint, and on the other ones while unavailable.
+// If the spore is built by the endpoint, the submission is only moved to the
+// next endpoint once the unavailable one reports not knowing the spore.
+// The endpoint accepting the spore is recorded.
+func (c *Client) write(ctx context.Context, uuid string, built bool, f func(api.SporeDBClient) error) error {
+	endpoints := c.routedFirst(uuid)
+	if len(endpoints) == 0 { // not connected through Connect
+		return f(c.client)
+	}
+
+	var e *endpoint
+	var err error
+loop:
+	for _, e = range endpoints {
+		err = f(e.client)
+		if !unavailable(err) || ctx.Err() != nil {
+			break
+		}
+
+		if built {
+			res, serr := e.client.Status(ctx, &api.Receipt{Uuid: uuid})
+			switch {
+			case serr != nil: // unknown outcome
+				c.setHealthy(e, false)
+				break loop
+			case res.Status != db.SporeStatus_UNKNOWN: // received, its receipt is returned again
+				err = f(e.client)
+				break loop
+			}
+		}
+		c.setHealthy(e, false)
+	}
+
+	if err != nil && (!built || !unavailable(err)) {
+		return err
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.route(uuid, e) // retries are sent to the same endpoint
+	if err != nil {
+		return err
+	}
+
+	if c.ReadYourWrites {
+		c.writes = append(c.writes, uuid)
+		if len(c.writes) > maxTrackedWrites {
+			c.forget(c.writes[0])
+		}
+	}
+	return nil
+}
+
+// route records the endpoint which accepted the spore.
+// The caller must hold the mutex.
+func (c *Client) route(uuid string, e *endpoint) {
+	if _, ok := c.routes[uuid]; !ok {
+		c.routedIDs = append(c.routedIDs, uuid)
+	}
+	c.routes[uuid] = e
+
+	if len(c.routedIDs) > maxTrackedRoutes {
+		delete(c.routes, c.routedIDs[0])
+		c.routedIDs = c.routedIDs[1:]
+	}
+}
+
+// forget stops tracking a write.
+// The caller must hold the mutex.
+func (c *Client) forget(uuid string) {
+	for i, w := range c.writes {
+		if w == uuid {
+			c.writes = append(c.writes[:i], c.writes[i+1:]...)
+			break
+		}
+	}
+
+	for _, e := range c.endpoints {
+		delete(e.seen, uuid)
+	}
+}
+
+// caughtUp returns true if the endpoint has seen every tracked write.
+// Writes seen by every endpoint are not tracked anymore.
+func (c *Client) caughtUp(ctx context.Context, e *endpoint) bool {
+	c.mutex.Lock()
+	var unseen []string
+	for _, uuid := range c.writes {
+		if !e.seen[uuid] {
+			unseen = append(unseen, uuid)
+		}
+	}
+	c.mutex.Unlock()
+
+	for _, uuid := range unseen {
+		res, err := e.client.Status(ctx, &api.Receipt{Uuid: uuid})
+		if err != nil || (res.Status != db.SporeStatus_APPLIED && res.Status != db.SporeStatus_DROPPED) {
+			return false
+		}
+		c.seen(e, uuid)
+	}
+	return true
+}
+
+// seen records that the endpoint has seen the write.
+func (c *Client) seen(e *endpoint, uuid string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	e.seen[uuid] = true
+	for _, other := range c.endpoints {
+		if !other.seen[uuid] {
+			return
+		}
+	}
+	c.forget(uuid)
+}
+
+// routed runs f on the endpoint which accepted the spore, or on the other ones while unavailable.
+func (c *Client) routed(ctx context.Context, uuid string, f func(api.SporeDBClient) error) error {
+	_, err := c.failover(ctx, c.routedFirst(uuid), f)
+	return err
+}
+
+// routedFirst returns the endpoints, the one which accepted the spore first,
+// then the healthy ones.
+func (c *Client) routedFirst(uuid string) []*endpoint {
+	c.mutex.Lock()
+	e := c.routes[uuid]
+	c.mutex.Unlock()
+
+	endpoints := c.ordered(false)
+	if e == nil {
+		return endpoints
+	}
+
+	routed := []*endpoint{e}
+	for _, o := range endpoints {
+		if o != e {
+			routed = append(routed, o)
+		}
+	}
+	return routed
+}
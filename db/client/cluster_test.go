@@ -0,0 +1,191 @@
+package client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+
+	"gitlab.com/SporeDB/sporedb/db"
+	"gitlab.com/SporeDB/sporedb/db/api"
+)
+
+// nodeAPI is a fake endpoint, recording the requests it served.
+type nodeAPI struct {
+	api.SporeDBClient
+	name     string
+	down     bool
+	refused  int // next submissions failing before their reception
+	lost     int // next submissions whose reply is lost
+	received map[string]bool
+	applied  map[string]bool
+	dropped  map[string]bool
+	served   []string
+}
+
+func (a *nodeAPI) serve(request string) error {
+	if a.down {
+		return &Error{Code: codes.Unavailable}
+	}
+	a.served = append(a.served, request)
+	return nil
+}
+
+func (a *nodeAPI) Get(ctx context.Context, in *api.Key, opts ...grpc.CallOption) (*api.Value, error) {
+	return &api.Value{Data: []byte(a.name)}, a.serve("get")
+}
+
+func (a *nodeAPI) Submit(ctx context.Context, in *api.Transaction, opts ...grpc.CallOption) (*api.Receipt, error) {
+	if a.refused > 0 {
+		a.refused--
+		return nil, &Error{Code: codes.Unavailable}
+	}
+
+	err := a.serve("submit")
+	if err == nil {
+		a.received[in.Uuid] = true
+	}
+	if err == nil && a.lost > 0 {
+		a.lost--
+		return nil, &Error{Code: codes.Unavailable}
+	}
+	return &api.Receipt{Uuid: in.Uuid, Status: a.status(in.Uuid)}, err
+}
+
+func (a *nodeAPI) Status(ctx context.Context, in *api.Receipt, opts ...grpc.CallOption) (*api.Receipt, error) {
+	return &api.Receipt{Uuid: in.Uuid, Status: a.status(in.Uuid)}, a.serve("status")
+}
+
+func (a *nodeAPI) status(uuid string) db.SporeStatus {
+	switch {
+	case a.applied[uuid]:
+		return db.SporeStatus_APPLIED
+	case a.dropped[uuid]:
+		return db.SporeStatus_DROPPED
+	case a.received[uuid]:
+		return db.SporeStatus_STAGED
+	}
+	return db.SporeStatus_UNKNOWN
+}
+
+func getTestingCluster(names ...string) (*Client, []*nodeAPI) {
+	c := &Client{}
+	c.routes = make(map[string]*endpoint)
+
+	var nodes []*nodeAPI
+	for _, name := range names {
+		n := &nodeAPI{
+			name:     name,
+			received: make(map[string]bool),
+			applied:  make(map[string]bool),
+			dropped:  make(map[string]bool),
+		}
+		nodes = append(nodes, n)
+		c.endpoints = append(c.endpoints, &endpoint{
+			addr:    name,
+			client:  n,
+			healthy: true,
+			seen:    make(map[string]bool),
+		})
+	}
+
+	c.client = nodes[0]
+	return c, nodes
+}
+
+func TestClient_Cluster(t *testing.T) {
+	c, nodes := getTestingCluster("a", "b", "c")
+	ctx := context.Background()
+
+	// Reads are spread
+	for i := 0; i < 3; i++ {
+		_, _, err := c.Get(ctx, "key")
+		require.Nil(t, err)
+	}
+	for _, n := range nodes {
+		require.Exactly(t, []string{"get"}, n.served)
+	}
+
+	// Submissions not received fail over, and statuses are asked to the accepting endpoint
+	nodes[0].refused = 1
+	id, err := c.Submit(ctx, &api.Transaction{})
+	require.Nil(t, err)
+	require.Exactly(t, []string{"b", "c"}, c.Endpoints())
+	require.Exactly(t, []string{"get", "status"}, nodes[0].served)
+
+	_, err = c.Status(ctx, id)
+	require.Nil(t, err)
+	require.Exactly(t, []string{"get", "submit", "status"}, nodes[1].served)
+
+	// Submissions received despite the failure are not built again by another endpoint
+	nodes[1].lost = 1
+	id, err = c.Submit(ctx, &api.Transaction{})
+	require.Nil(t, err)
+	require.True(t, nodes[1].received[id])
+	require.Empty(t, nodes[2].served[1:])
+
+	// Submissions with an unknown outcome are retried on the same endpoint
+	nodes[1].down = true
+	tx := &api.Transaction{}
+	_, err = c.Submit(ctx, tx)
+	require.True(t, unavailable(err))
+	require.Empty(t, nodes[2].served[1:])
+
+	nodes[1].down = false
+	_, err = c.Submit(ctx, tx)
+	require.Nil(t, err)
+	require.True(t, nodes[1].received[tx.Uuid])
+	require.Empty(t, nodes[2].served[1:])
+
+	// Failover stops with the context
+	nodes[1].down = true
+	done, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = c.Status(done, tx.Uuid)
+	require.True(t, unavailable(err))
+	require.Empty(t, nodes[2].served[1:])
+
+	// Unavailable endpoints are used as a last resort
+	nodes[0].down, nodes[2].down = true, true
+	_, _, err = c.Get(ctx, "key")
+	require.True(t, unavailable(err))
+	require.Empty(t, c.Endpoints())
+
+	// Deadlines do not trigger failovers
+	require.False(t, unavailable(&Error{Code: codes.DeadlineExceeded}))
+}
+
+func TestClient_ReadYourWrites(t *testing.T) {
+	c, nodes := getTestingCluster("a", "b")
+	c.ReadYourWrites = true
+	ctx := context.Background()
+
+	id, err := c.Submit(ctx, &api.Transaction{})
+	require.Nil(t, err)
+
+	nodes[1].applied[id] = true
+	for i := 0; i < 2; i++ {
+		value, _, err := c.Get(ctx, "key")
+		require.Nil(t, err)
+		require.Exactly(t, "b", string(value))
+	}
+
+	nodes[0].applied[id] = true
+	value, _, err := c.Get(ctx, "key")
+	require.Nil(t, err)
+	require.Exactly(t, "a", string(value))
+	require.Empty(t, c.writes)
+}
+
+func TestClient_SubmitDropped(t *testing.T) {
+	c, nodes := getTestingCluster("a")
+	tx := &api.Transaction{Uuid: "dropped"}
+	nodes[0].dropped[tx.Uuid] = true
+
+	id, err := c.Submit(context.Background(), tx)
+	require.True(t, IsConflict(err))
+	require.Exactly(t, "DROPPED", Reason(err))
+	require.Exactly(t, tx.Uuid, id)
+}
@@ -0,0 +1,86 @@
+package client
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"gitlab.com/SporeDB/sporedb/db/api"
+)
+
+// Error is an error returned by the server, with its gRPC status code and
+// the stable reason of the database error, such as "VERSION_MISMATCH".
+type Error struct {
+	Code    codes.Code
+	Reason  string // empty for errors unknown to the database
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// GRPCStatus returns the gRPC status of the error.
+func (e *Error) GRPCStatus() *status.Status {
+	return status.New(e.Code, e.Message)
+}
+
+// fromStatus converts a gRPC status error to an Error.
+// Other errors are returned unchanged.
+func fromStatus(err error) error {
+	s, ok := status.FromError(err)
+	if !ok || s == nil {
+		return err
+	}
+
+	e := &Error{Code: s.Code(), Message: s.Message()}
+	for _, d := range s.Details() {
+		if detail, ok := d.(*api.ErrorDetail); ok {
+			e.Reason = detail.Reason
+		}
+	}
+	return e
+}
+
+// errorInterceptor converts the errors returned by the server to Errors.
+func errorInterceptor(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	err := invoker(ctx, method, req, reply, cc, opts...)
+	if err != nil {
+		err = fromStatus(err)
+	}
+	return err
+}
+
+func code(err error) codes.Code {
+	if e, ok := err.(*Error); ok {
+		return e.Code
+	}
+	return codes.Unknown
+}
+
+// Reason returns the reason of a database error, such as "OP_DISABLED_KEY".
+// It returns an empty string for other errors.
+func Reason(err error) string {
+	if e, ok := err.(*Error); ok {
+		return e.Reason
+	}
+	return ""
+}
+
+// IsNotFound returns true if the error is caused by a missing key, spore or policy.
+func IsNotFound(err error) bool {
+	return code(err) == codes.NotFound
+}
+
+// IsConflict returns true if the error is caused by a conflict with the state
+// or with other spores. The transaction may be retried.
+func IsConflict(err error) bool {
+	return code(err) == codes.Aborted
+}
+
+// IsForbidden returns true if the error is caused by an operation forbidden by the policy.
+func IsForbidden(err error) bool {
+	return code(err) == codes.PermissionDenied
+}
@@ -13,7 +13,11 @@ import (
 
 // Get gets the key from the endpoint.
 func (c *Client) Get(ctx context.Context, key string) (value []byte, v *version.V, err error) {
-	res, err := c.client.Get(ctx, &api.Key{Key: key})
+	var res *api.Value
+	err = c.read(ctx, func(a api.SporeDBClient) (err error) {
+		res, err = a.Get(ctx, &api.Key{Key: key})
+		return
+	})
 	if res != nil {
 		value = res.Data
 		v = res.Version
@@ -24,7 +28,11 @@ func (c *Client) Get(ctx context.Context, key string) (value []byte, v *version.
 
 // Members returns the slice of every element of a container.
 func (c *Client) Members(ctx context.Context, key string) (values [][]byte, v *version.V, err error) {
-	members, err := c.client.Members(ctx, &api.Key{Key: key})
+	var members *api.Values
+	err = c.read(ctx, func(a api.SporeDBClient) (err error) {
+		members, err = a.Members(ctx, &api.Key{Key: key})
+		return
+	})
 	if members != nil {
 		values = members.Data
 		v = members.Version
@@ -35,8 +43,12 @@ func (c *Client) Members(ctx context.Context, key string) (values [][]byte, v *v
 
 // Contains returns wether or not a specific value is present in a container.
 func (c *Client) Contains(ctx context.Context, key string, value []byte) (contains bool, err error) {
-	boolean, err := c.client.Contains(ctx, &api.KeyValue{Key: key, Value: value})
-	contains = boolean.Boolean
+	var boolean *api.Boolean
+	err = c.read(ctx, func(a api.SporeDBClient) (err error) {
+		boolean, err = a.Contains(ctx, &api.KeyValue{Key: key, Value: value})
+		return
+	})
+	contains = boolean.GetBoolean()
 	return
 }
 
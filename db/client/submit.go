@@ -6,6 +6,8 @@ import (
 	"io"
 	"strings"
 
+	"github.com/golang/protobuf/ptypes"
+	uuid "github.com/satori/go.uuid"
 	"google.golang.org/grpc"
 
 	"gitlab.com/SporeDB/sporedb/db"
@@ -13,16 +15,197 @@ import (
 )
 
 // Submit submits the transaction to the endpoint.
-func (c *Client) Submit(ctx context.Context, tx *api.Transaction) (uuid string, err error) {
-	res, err := c.client.Submit(ctx, tx)
+// A new uuid is assigned to the transaction if it has none, so that the
+// same transaction can be submitted again, for instance after a timeout,
+// without being applied twice. ErrTxnDropped is returned along with the uuid
+// if the endpoint already dropped the spore.
+func (c *Client) Submit(ctx context.Context, tx *api.Transaction) (id string, err error) {
+	if tx.Uuid == "" {
+		tx.Uuid = uuid.NewV4().String()
+	}
+
+	var res *api.Receipt
+	err = c.write(ctx, tx.Uuid, true, func(a api.SporeDBClient) (err error) {
+		res, err = a.Submit(ctx, tx)
+		return
+	})
+	if err != nil {
+		return
+	}
+
+	return receipt(res)
+}
+
+// receipt returns the uuid of the submitted spore, and ErrTxnDropped if the
+// endpoint reports it as dropped, for instance when it was vetoed before.
+func receipt(res *api.Receipt) (string, error) {
+	if res.Status == db.SporeStatus_DROPPED {
+		return res.Uuid, ErrTxnDropped
+	}
+	return res.Uuid, nil
+}
+
+// Status returns the status of a spore, as known by the endpoint which accepted it.
+func (c *Client) Status(ctx context.Context, uuid string) (db.SporeStatus, error) {
+	var res *api.Receipt
+	err := c.routed(ctx, uuid, func(a api.SporeDBClient) (err error) {
+		res, err = a.Status(ctx, &api.Receipt{Uuid: uuid})
+		return
+	})
+	if err != nil {
+		return db.SporeStatus_UNKNOWN, err
+	}
+
+	return res.Status, nil
+}
+
+func (c *Client) processSTATUS(uuid string) {
+	ctx, done := c.ctx()
+	defer done()
+
+	status, err := c.Status(ctx, uuid)
 	if err != nil {
+		fmt.Println("Error:", grpc.ErrorDesc(err))
 		return
 	}
 
-	uuid = res.Uuid
+	fmt.Println("Status:", status)
+}
+
+// SubmitSpore submits a spore signed by the client, whose key is known by the endpoint.
+// See db.Spore.Sign.
+func (c *Client) SubmitSpore(ctx context.Context, s *db.Spore) (uuid string, err error) {
+	var res *api.Receipt
+	err = c.write(ctx, s.Uuid, false, func(a api.SporeDBClient) (err error) {
+		res, err = a.SubmitSpore(ctx, s)
+		return
+	})
+	if err != nil {
+		return
+	}
+
+	return receipt(res)
+}
+
+// Cancel cancels a pending spore previously submitted to the endpoint,
+// until its quorum is reached.
+func (c *Client) Cancel(ctx context.Context, uuid string) error {
+	return c.routed(ctx, uuid, func(a api.SporeDBClient) error {
+		_, err := a.Cancel(ctx, &api.Receipt{Uuid: uuid})
+		return err
+	})
+}
+
+// Prepare builds the spore of a transaction to be cosigned, emitted by the endpoint.
+func (c *Client) Prepare(ctx context.Context, tx *api.Transaction) (s *db.Spore, err error) {
+	e, err := c.failover(ctx, c.ordered(false), func(a api.SporeDBClient) (err error) {
+		s, err = a.Prepare(ctx, tx)
+		return
+	})
+
+	if err == nil && e != nil { // the spore is to be proposed to its emitter
+		c.mutex.Lock()
+		c.route(s.Uuid, e)
+		c.mutex.Unlock()
+	}
 	return
 }
 
+// Cosign returns the spore cosigned by the endpoint.
+func (c *Client) Cosign(ctx context.Context, s *db.Spore) (*db.Spore, error) {
+	return c.client.Cosign(ctx, s)
+}
+
+// Propose submits a cosigned spore to its emitting endpoint.
+func (c *Client) Propose(ctx context.Context, s *db.Spore) (uuid string, err error) {
+	var res *api.Receipt
+	err = c.routed(ctx, s.Uuid, func(a api.SporeDBClient) (err error) {
+		res, err = a.Propose(ctx, s)
+		return
+	})
+	if err != nil {
+		return
+	}
+
+	return receipt(res)
+}
+
+func (c *Client) processCANCEL(uuid string) {
+	ctx, done := c.ctx()
+	defer done()
+
+	if err := c.Cancel(ctx, uuid); err != nil {
+		fmt.Println("Error:", grpc.ErrorDesc(err))
+		return
+	}
+
+	fmt.Println("Cancelled:", uuid)
+}
+
+// Simulate runs the endorsement checks of the transaction on the endpoint, without submitting it.
+func (c *Client) Simulate(ctx context.Context, tx *api.Transaction) (r *api.Simulation, err error) {
+	err = c.read(ctx, func(a api.SporeDBClient) (err error) {
+		r, err = a.Simulate(ctx, tx)
+		return
+	})
+	return
+}
+
+func (c *Client) processSIMULATE(arg string) {
+	args := strings.SplitN(arg, " ", 3)
+	op := strings.ToUpper(args[0])
+	if _, ok := db.Operation_Op_value[op]; !ok || len(args) < 3 {
+		fmt.Println("SIMULATE function expects three arguments: (op, key, data)")
+		return
+	}
+
+	ctx, done := c.ctx()
+	defer done()
+
+	r, err := c.Simulate(ctx, c.transaction(op, args[1], args[2]))
+	if err != nil {
+		fmt.Println("Error:", grpc.ErrorDesc(err))
+		return
+	}
+
+	for _, check := range r.Checks {
+		if check.Passed {
+			fmt.Println("Passed:", check.Name)
+		} else {
+			fmt.Println("Failed:", check.Name, "-", check.Error)
+		}
+	}
+
+	if r.Conflict != "" {
+		fmt.Println("Conflicting with:", r.Conflict)
+	}
+
+	for key, value := range r.Values {
+		fmt.Printf("Value: %s = %q\n", key, value)
+	}
+
+	for _, u := range r.Usages {
+		fmt.Printf("Usage: %s %d -> %d bytes (max %d), emitter %d -> %d bytes\n",
+			u.Policy, u.Size, u.NewSize, u.MaxSize, u.Emitter.GetSize(), u.EmitterNewSize)
+	}
+}
+
+// transaction returns a single operation transaction, with the current policy and timeout.
+func (c *Client) transaction(op, key, data string) *api.Transaction {
+	tx := &api.Transaction{
+		Operations: []*db.Operation{{
+			Key:  key,
+			Op:   db.Operation_Op(db.Operation_Op_value[op]),
+			Data: []byte(data),
+		}},
+		Policy: c.policy,
+	}
+	if c.timeout > 0 {
+		tx.Timeout = ptypes.DurationProto(c.timeout)
+	}
+	return tx
+}
+
 func (c *Client) processGeneric2(op string) func(arg string) {
 	return func(arg string) {
 		arg1, arg2, err := split2args(arg)
@@ -30,14 +213,7 @@ func (c *Client) processGeneric2(op string) func(arg string) {
 			fmt.Println(op, "function expects two arguments: (key, data)")
 			return
 		}
-		tx := &api.Transaction{
-			Operations: []*db.Operation{{
-				Key:  arg1,
-				Op:   db.Operation_Op(db.Operation_Op_value[op]),
-				Data: []byte(arg2),
-			}},
-			Policy: c.policy,
-		}
+		tx := c.transaction(op, arg1, arg2)
 
 		ctx, done := c.ctx()
 		defer done()
@@ -0,0 +1,212 @@
+package client
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"google.golang.org/grpc/codes"
+
+	"gitlab.com/SporeDB/sporedb/db"
+	"gitlab.com/SporeDB/sporedb/db/api"
+	"gitlab.com/SporeDB/sporedb/db/version"
+)
+
+// Default retry parameters of optimistic transactions.
+const (
+	DefaultTxnRetries = 5
+	DefaultTxnBackoff = 100 * time.Millisecond
+)
+
+// maxTxnBackoff caps the exponential backoff between two attempts of a transaction.
+const maxTxnBackoff = 10 * time.Second
+
+// statusPollInterval is the delay between two status requests while waiting for a spore.
+const statusPollInterval = 50 * time.Millisecond
+
+// Error messages for optimistic transactions.
+// They are conflicts, see IsConflict.
+var (
+	ErrTxnDropped  = &Error{Code: codes.Aborted, Reason: "DROPPED", Message: "the transaction has been dropped by the cluster"}
+	ErrTxnReadSkew = &Error{Code: codes.Aborted, Reason: "VERSION_MISMATCH", Message: "the key changed between two reads of the transaction"}
+)
+
+// Txn is an optimistic transaction. The versions of the keys it reads are
+// required by its operations, so that it is only applied if none of these
+// keys changed in the meantime.
+type Txn struct {
+	Policy   string
+	Policies []string      // additional policies of the operations
+	Timeout  time.Duration // requested spore timeout, zero for the server default
+
+	c            *Client
+	requirements map[string]*version.V
+	operations   []*db.Operation
+}
+
+// Txn starts an optimistic transaction, with the current policy and timeout.
+func (c *Client) Txn() *Txn {
+	return &Txn{
+		Policy:       c.policy,
+		Timeout:      c.timeout,
+		c:            c,
+		requirements: make(map[string]*version.V),
+	}
+}
+
+// require records the version of a key read by the transaction.
+func (t *Txn) require(key string, v *version.V) error {
+	if previous, ok := t.requirements[key]; ok {
+		if previous.Matches(v) != nil {
+			return ErrTxnReadSkew
+		}
+		return nil
+	}
+
+	t.requirements[key] = v
+	return nil
+}
+
+// Get gets the key from the endpoint, and requires its current version.
+// Missing keys are required to stay missing, and return an error matching IsNotFound.
+func (t *Txn) Get(ctx context.Context, key string) ([]byte, error) {
+	value, v, err := t.c.Get(ctx, key)
+	if IsNotFound(err) {
+		v = version.NoVersion
+	} else if err != nil {
+		return nil, err
+	}
+
+	if rerr := t.require(key, v); rerr != nil {
+		return nil, rerr
+	}
+	return value, err
+}
+
+// Members returns the slice of every element of a container, and requires its current version.
+// Missing keys are required to stay missing, and return an error matching IsNotFound.
+func (t *Txn) Members(ctx context.Context, key string) ([][]byte, error) {
+	values, v, err := t.c.Members(ctx, key)
+	if IsNotFound(err) {
+		v = version.NoVersion
+	} else if err != nil {
+		return nil, err
+	}
+
+	if rerr := t.require(key, v); rerr != nil {
+		return nil, rerr
+	}
+	return values, err
+}
+
+// Do adds an operation to the transaction.
+func (t *Txn) Do(op db.Operation_Op, key string, data []byte) {
+	t.operations = append(t.operations, &db.Operation{Key: key, Op: op, Data: data})
+}
+
+// Set adds a SET operation to the transaction.
+func (t *Txn) Set(key string, data []byte) {
+	t.Do(db.Operation_SET, key, data)
+}
+
+// transaction returns the API transaction of the operations and requirements.
+func (t *Txn) transaction() *api.Transaction {
+	tx := &api.Transaction{
+		Policy:       t.Policy,
+		Policies:     t.Policies,
+		Requirements: t.requirements,
+		Operations:   t.operations,
+	}
+	if t.Timeout > 0 {
+		tx.Timeout = ptypes.DurationProto(t.Timeout)
+	}
+	return tx
+}
+
+// Commit submits the transaction and waits for its application.
+// Read-only transactions are not submitted.
+func (t *Txn) Commit(ctx context.Context) error {
+	if len(t.operations) == 0 {
+		return nil
+	}
+
+	id, err := t.c.Submit(ctx, t.transaction())
+	if err != nil {
+		return err
+	}
+
+	status, err := t.c.Wait(ctx, id)
+	if err == nil && status == db.SporeStatus_DROPPED {
+		err = ErrTxnDropped
+	}
+	return err
+}
+
+// Wait waits until the spore is applied or dropped, as known by the endpoint,
+// and returns its final status.
+func (c *Client) Wait(ctx context.Context, uuid string) (db.SporeStatus, error) {
+	for {
+		status, err := c.Status(ctx, uuid)
+		if err != nil || status == db.SporeStatus_APPLIED || status == db.SporeStatus_DROPPED {
+			return status, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return status, ctx.Err()
+		case <-time.After(statusPollInterval):
+		}
+	}
+}
+
+// Update runs f within an optimistic transaction, then commits it.
+//
+// On conflicts, such as a version mismatch of the keys read by f, the
+// transaction is run again from scratch after an exponential backoff, capped
+// to 10 seconds, up to TxnRetries times. f may thus be called several times, and should not have
+// side effects. Errors returned by f abort the transaction, unless they are
+// conflicts.
+func (c *Client) Update(ctx context.Context, f func(t *Txn) error) error {
+	retries, backoff := c.TxnRetries, c.TxnBackoff
+	if retries == 0 {
+		retries = DefaultTxnRetries
+	}
+	if backoff == 0 {
+		backoff = DefaultTxnBackoff
+	}
+
+	for attempt := 0; ; attempt++ {
+		t := c.Txn()
+		err := f(t)
+		if err == nil {
+			err = t.Commit(ctx)
+		}
+
+		if !IsConflict(err) || attempt >= retries {
+			return err
+		}
+
+		// Random jitter, so that conflicting clients do not retry together
+		delay := txnBackoff(backoff, attempt)
+		delay += time.Duration(rand.Int63n(int64(delay)))
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(delay):
+		}
+	}
+}
+
+// txnBackoff returns the backoff before the next attempt, doubled at each
+// attempt up to maxTxnBackoff.
+func txnBackoff(backoff time.Duration, attempt int) time.Duration {
+	for ; attempt > 0 && backoff < maxTxnBackoff; attempt-- {
+		backoff *= 2
+	}
+	if backoff > maxTxnBackoff {
+		backoff = maxTxnBackoff
+	}
+	return backoff
+}
@@ -0,0 +1,83 @@
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+
+	"gitlab.com/SporeDB/sporedb/db"
+	"gitlab.com/SporeDB/sporedb/db/api"
+	"gitlab.com/SporeDB/sporedb/db/version"
+)
+
+// txnAPI is a fake endpoint, whose single key is changed concurrently
+// during the first submissions.
+type txnAPI struct {
+	api.SporeDBClient
+	value     []byte
+	version   *version.V
+	conflicts int
+	submitted []*api.Transaction
+}
+
+func (a *txnAPI) Get(ctx context.Context, in *api.Key, opts ...grpc.CallOption) (*api.Value, error) {
+	if a.value == nil {
+		return nil, &Error{Code: codes.NotFound}
+	}
+	return &api.Value{Data: a.value, Version: a.version}, nil
+}
+
+func (a *txnAPI) Submit(ctx context.Context, in *api.Transaction, opts ...grpc.CallOption) (*api.Receipt, error) {
+	a.submitted = append(a.submitted, in)
+	if a.conflicts > 0 {
+		a.conflicts--
+		a.value, a.version = []byte("concurrent"), &version.V{Hash: []byte{byte(a.conflicts)}}
+	}
+
+	if in.Requirements["key"].Matches(a.version) != nil {
+		return nil, &Error{Code: codes.Aborted, Reason: "VERSION_MISMATCH"}
+	}
+
+	a.value, a.version = in.Operations[0].Data, &version.V{Hash: in.Operations[0].Data}
+	return &api.Receipt{Uuid: in.Uuid, Status: db.SporeStatus_APPLIED}, nil
+}
+
+func (a *txnAPI) Status(ctx context.Context, in *api.Receipt, opts ...grpc.CallOption) (*api.Receipt, error) {
+	return &api.Receipt{Uuid: in.Uuid, Status: db.SporeStatus_APPLIED}, nil
+}
+
+func TestClient_Update(t *testing.T) {
+	a := &txnAPI{version: version.NoVersion, conflicts: 2}
+	c := &Client{client: a, TxnBackoff: time.Millisecond}
+
+	increment := func(t *Txn) error {
+		value, err := t.Get(context.Background(), "key")
+		if err != nil && !IsNotFound(err) {
+			return err
+		}
+		t.Set("key", append(value, '+'))
+		return nil
+	}
+
+	require.Nil(t, c.Update(context.Background(), increment))
+	require.Exactly(t, "concurrent+", string(a.value))
+	require.Len(t, a.submitted, 3)
+	require.Exactly(t, version.NoVersion, a.submitted[0].Requirements["key"])
+
+	a.conflicts = 10
+	c.TxnRetries = 2
+	err := c.Update(context.Background(), increment)
+	require.True(t, IsConflict(err))
+	require.Exactly(t, "VERSION_MISMATCH", Reason(err))
+	require.Len(t, a.submitted, 6)
+}
+
+func TestTxnBackoff(t *testing.T) {
+	require.Exactly(t, 400*time.Millisecond, txnBackoff(100*time.Millisecond, 2))
+	require.Exactly(t, maxTxnBackoff, txnBackoff(100*time.Millisecond, 100))
+	require.Exactly(t, maxTxnBackoff, txnBackoff(time.Hour, 0))
+}
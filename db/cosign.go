@@ -0,0 +1,188 @@
+package db
+
+import (
+	"bytes"
+	"errors"
+	"sort"
+
+	"gitlab.com/SporeDB/sporedb/myc/sec"
+)
+
+// Some writes must be authorized by several parties. A policy specification
+// may list cosigners, whose signatures are required to modify the matching
+// keys. The emitter and the cosigners of a spore sign the same hash, computed
+// on the spore without any signature, so that a partially signed spore may be
+// passed between the parties before its submission by the emitter.
+//
+// The cosignatures are not covered by the emitter's signature, and may thus be
+// reordered by relays. They are sorted by emitter before hashing the spore for
+// endorsements, so that every node computes the same hash.
+
+// Error messages for cosignatures.
+var (
+	ErrForeignEmitter        = errors.New("the spore is to be submitted by another node")
+	ErrMissingCosignature    = errors.New("the spore lacks cosignatures required by the policy")
+	ErrDuplicatedCosignature = errors.New("the spore is cosigned twice by the same party")
+)
+
+// signingHash returns the hash signed by the emitter and the cosigners of the spore.
+// The deadline of spores with a validity is set on submission, and is not signed.
+func (s *Spore) signingHash() []byte {
+	unsigned := *s
+	unsigned.Signature, unsigned.Cosignatures = nil, nil
+	if unsigned.ValidUntil != nil {
+		unsigned.Deadline = nil
+	}
+	return hashMessage(&unsigned)
+}
+
+// canonicalHash returns the hash of the spore, its cosignatures being sorted by emitter.
+func (s *Spore) canonicalHash() []byte {
+	if len(s.Cosignatures) < 2 {
+		return hashMessage(s)
+	}
+
+	sorted := *s
+	sorted.Cosignatures = make([]*Cosignature, len(s.Cosignatures))
+	copy(sorted.Cosignatures, s.Cosignatures)
+	sortCosignatures(sorted.Cosignatures)
+	return hashMessage(&sorted)
+}
+
+// sortCosignatures sorts the cosignatures by emitter, then by signature.
+func sortCosignatures(cosignatures []*Cosignature) {
+	sort.Slice(cosignatures, func(i, j int) bool {
+		a, b := cosignatures[i], cosignatures[j]
+		if a.Emitter != b.Emitter {
+			return a.Emitter < b.Emitter
+		}
+		return bytes.Compare(a.Signature, b.Signature) < 0
+	})
+}
+
+// Sign signs the spore as its emitter, with the private key of the keyring.
+// It may be used offline, the emitter being the identity of the key on the nodes.
+func (s *Spore) Sign(k sec.KeyRing) (err error) {
+	s.Signature, err = k.Sign(s.signingHash())
+	return
+}
+
+// Cosign adds a cosignature of the spore, with the private key of the keyring.
+// The identity is the one of the key on the nodes, and its previous cosignature is replaced.
+// The cosignatures are kept sorted by emitter.
+func (s *Spore) Cosign(identity string, k sec.KeyRing) error {
+	signature, err := k.Sign(s.signingHash())
+	if err != nil {
+		return err
+	}
+
+	cosignatures := []*Cosignature{{Emitter: identity, Signature: signature}}
+	for _, c := range s.Cosignatures {
+		if c.Emitter != identity {
+			cosignatures = append(cosignatures, c)
+		}
+	}
+
+	sortCosignatures(cosignatures)
+	s.Cosignatures = cosignatures
+	return nil
+}
+
+// Cosign adds the signature of the node to the cosignatures of the spore.
+// The emitter does not cosign its own spores.
+func (db *DB) Cosign(s *Spore) error {
+	if s.Emitter == db.Identity {
+		return nil // the emitter's signature is counted anyway
+	}
+
+	return s.Cosign(db.Identity, db.KeyRing)
+}
+
+// signers returns the public keys of the emitter and the cosigners of the spore.
+// The emitter's signature is expected to be verified already.
+func (db *DB) signers(s *Spore) (map[string]bool, error) {
+	hash := s.signingHash()
+	signers := make(map[string]bool)
+
+	identity := func(emitter string) string {
+		if emitter == db.Identity {
+			return "" // local signature case
+		}
+		return emitter
+	}
+
+	pub, _, err := db.KeyRing.GetPublic(identity(s.Emitter))
+	if err != nil {
+		return nil, err
+	}
+	signers[string(pub)] = true
+
+	for _, c := range s.Cosignatures {
+		if err = db.KeyRing.Verify(identity(c.Emitter), hash, c.Signature); err != nil {
+			return nil, err
+		}
+
+		pub, _, err = db.KeyRing.GetPublic(identity(c.Emitter))
+		if err != nil {
+			return nil, err
+		}
+
+		if signers[string(pub)] {
+			return nil, ErrDuplicatedCosignature
+		}
+		signers[string(pub)] = true
+	}
+
+	return signers, nil
+}
+
+// checkCosignatures checks that the spore is signed by the cosigners required
+// by the specifications matching its operations.
+func (db *DB) checkCosignatures(s *Spore) error {
+	var signers map[string]bool
+	for _, op := range s.Operations {
+		policy := s.opPolicy(op)
+		db.policiesMutex.RLock()
+		p, c := db.policies[policy], db.policiesComp[policy]
+		db.policiesMutex.RUnlock()
+
+		if p == nil {
+			return ErrUnknownPolicy
+		}
+
+		for i, spec := range p.Specs {
+			if len(spec.Cosigners) == 0 || !c.regexes[i].MatchString(op.Key) {
+				continue
+			}
+
+			if signers == nil {
+				var err error
+				if signers, err = db.signers(s); err != nil {
+					return err
+				}
+			}
+
+			if !spec.cosigned(signers) {
+				return ErrMissingCosignature
+			}
+		}
+	}
+
+	return nil
+}
+
+// cosigned returns true if enough cosigners of the specification are among the signers.
+func (s *OSpec) cosigned(signers map[string]bool) bool {
+	quorum := s.CosignQuorum
+	if quorum == 0 {
+		quorum = uint64(len(s.Cosigners))
+	}
+
+	var n uint64
+	for _, c := range s.Cosigners {
+		if signers[string(c.Public)] {
+			n++
+		}
+	}
+	return n >= quorum
+}
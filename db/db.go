@@ -3,7 +3,8 @@ package db
 
 import (
 	"crypto/sha512"
-	"regexp"
+	"errors"
+	"sort"
 	"sync"
 	"time"
 
@@ -36,9 +37,15 @@ type DB struct {
 	// See gitlab.com/SporeDB/sporedb/myc/protocol
 	Messages chan proto.Message
 
+	// StallInterval is the delay after which the endorsements of a spore still
+	// staged are requested to peers, in case their broadcast has been missed.
+	// A zero interval disables the requests. It must be set before Start.
+	StallInterval time.Duration
+
 	// Policy management
-	policies    map[string]*Policy
-	policiesReg map[string][]*regexp.Regexp
+	policies      map[string]*Policy
+	policiesComp  map[string]*compiledPolicy
+	policiesMutex sync.RWMutex
 
 	// Spore flow management
 	//
@@ -59,88 +66,251 @@ type DB struct {
 	waiting map[string]*dbTrigger
 	staging map[string]*dbTrigger
 	applied map[string]time.Time
+	vetoed  map[string]time.Time // deadlines of spores we gave up
+
+	// Endorsements of recently applied spores, guarded by appliedMutex.
+	// They are served to peers which missed them, until the grace period ends.
+	endorsed map[string][]*Endorsement
+
+	// Indexes of waiting and staging lists by key, guarded by their lists' mutexes
+	waitingIndex    sporeIndex
+	dependencyIndex dependencyIndex // waiting spores by dependency
+	stagingIndex    sporeIndex
+
+	// Identities whose endorsements are ignored
+	excluded      map[string]bool
+	excludedMutex sync.RWMutex
 
 	// Paralellism management
 	waitingMutex sync.RWMutex
 	stagingMutex sync.RWMutex
 	appliedMutex sync.Mutex
 	cache        *lru.Cache
+	orphans      *lru.Cache // endorsements of unknown spores
+	cancels      *lru.Cache // cancellations of unknown spores, guarded by orphansMutex
+	orphansMutex sync.Mutex
 	gc           chan *Spore
+	done         chan struct{} // closed when the database is stopped
 	cleanTicker  *time.Ticker
 }
 
 type dbTrigger struct {
 	timer        *time.Timer
+	stall        *time.Timer // requests missing endorsements, see DB.StallInterval
 	endorsements []*Endorsement
+	vetoes       []string // endorsers who withdrew their endorsement
 	spore        *Spore
+	committed    bool // quorum reached, applied once its not-before time is over
+	cancelled    bool // cancellation received once committed, and relayed
+
+	// policies are the policies the spore has been endorsed under.
+	// They are kept until the spore settles, even if reloaded meanwhile.
+	policies []*Policy
 }
 
 // NewDB instanciates a new database with clean initialization.
 func NewDB(s Store, identity string, keyring sec.KeyRing) *DB {
 	c, _ := lru.New(32)
+	o, _ := lru.New(maxOrphanSpores)
+	oc, _ := lru.New(maxOrphanSpores)
 	return &DB{
-		Store:       s,
-		Identity:    identity,
-		KeyRing:     keyring,
-		Messages:    make(chan proto.Message, 16),
-		policies:    make(map[string]*Policy),
-		policiesReg: make(map[string][]*regexp.Regexp),
-		staging:     make(map[string]*dbTrigger),
-		waiting:     make(map[string]*dbTrigger),
-		applied:     make(map[string]time.Time),
-		cache:       c,
-		gc:          make(chan *Spore),
+		Store:           s,
+		Identity:        identity,
+		KeyRing:         keyring,
+		Messages:        make(chan proto.Message, 16),
+		StallInterval:   DefaultStallInterval,
+		policies:        make(map[string]*Policy),
+		policiesComp:    make(map[string]*compiledPolicy),
+		staging:         make(map[string]*dbTrigger),
+		waiting:         make(map[string]*dbTrigger),
+		stagingIndex:    make(sporeIndex),
+		waitingIndex:    make(sporeIndex),
+		dependencyIndex: make(dependencyIndex),
+		applied:         make(map[string]time.Time),
+		vetoed:          make(map[string]time.Time),
+		endorsed:        make(map[string][]*Endorsement),
+		cache:           c,
+		orphans:         o,
+		cancels:         oc,
+		gc:              make(chan *Spore),
+		done:            make(chan struct{}),
 	}
 }
 
 // AddPolicy registers a new policy for the database.
+// It returns ErrClaimOverlap if the policy claims keys of another policy.
 func (db *DB) AddPolicy(p *Policy) error {
-	regexes, err := p.compileRegexes()
+	c, err := p.compile()
 	if err != nil {
 		return err
 	}
 
-	db.policies[p.Uuid], db.policiesReg[p.Uuid] = p, regexes
+	db.policiesMutex.Lock()
+	defer db.policiesMutex.Unlock()
+
+	if err = checkClaims(db.policies, p); err != nil {
+		return err
+	}
+
+	db.policies[p.Uuid], db.policiesComp[p.Uuid] = p, c
 	return nil
 }
 
+// RemovePolicy unregisters a policy from the database.
+// Spores already endorsed under this policy keep it until they settle.
+func (db *DB) RemovePolicy(uuid string) {
+	db.policiesMutex.Lock()
+	delete(db.policies, uuid)
+	delete(db.policiesComp, uuid)
+	db.policiesMutex.Unlock()
+}
+
+// ReplacePolicies replaces the previous policies registered by AddPolicy with
+// the provided ones, in one step. Policies stored on-ledger take precedence,
+// and are neither replaced nor removed. Nothing is changed if one of the
+// policies is invalid, or if their claims overlap.
+func (db *DB) ReplacePolicies(previous []string, policies []*Policy) error {
+	compiled := make([]*compiledPolicy, len(policies))
+	for i, p := range policies {
+		c, err := p.compile()
+		if err != nil {
+			return err
+		}
+		compiled[i] = c
+	}
+
+	stored := make(map[string]bool)
+	for _, uuid := range previous {
+		stored[uuid] = db.isStoredPolicy(uuid)
+	}
+	for _, p := range policies {
+		stored[p.Uuid] = db.isStoredPolicy(p.Uuid)
+	}
+
+	db.policiesMutex.Lock()
+	defer db.policiesMutex.Unlock()
+
+	next := make(map[string]*Policy, len(db.policies))
+	nextComp := make(map[string]*compiledPolicy, len(db.policiesComp))
+	for uuid, p := range db.policies {
+		next[uuid], nextComp[uuid] = p, db.policiesComp[uuid]
+	}
+
+	for _, uuid := range previous {
+		if !stored[uuid] {
+			delete(next, uuid)
+			delete(nextComp, uuid)
+		}
+	}
+
+	for i, p := range policies {
+		if stored[p.Uuid] {
+			continue
+		}
+
+		if err := checkClaims(next, p); err != nil {
+			return err
+		}
+		next[p.Uuid], nextComp[p.Uuid] = p, compiled[i]
+	}
+
+	db.policies, db.policiesComp = next, nextComp
+	return nil
+}
+
+// Policies returns the sorted list of registered policies' uuids.
+func (db *DB) Policies() []string {
+	db.policiesMutex.RLock()
+	defer db.policiesMutex.RUnlock()
+
+	uuids := make([]string, 0, len(db.policies))
+	for uuid := range db.policies {
+		uuids = append(uuids, uuid)
+	}
+
+	sort.Strings(uuids)
+	return uuids
+}
+
+// getPolicy returns the currently registered policy, or nil if unknown.
+// It is thread-safe.
+func (db *DB) getPolicy(uuid string) *Policy {
+	db.policiesMutex.RLock()
+	defer db.policiesMutex.RUnlock()
+	return db.policies[uuid]
+}
+
 // Start starts the database, waiting for incoming spores to be processed.
 // It can either work in blocking or non-blocking modes.
+// The consensus state persisted in the store is restored first.
 func (db *DB) Start(blocking bool) {
+	if err := db.restore(); err != nil {
+		zap.L().Error("Unable to restore the consensus state",
+			zap.Error(err),
+		)
+	}
+
 	var wg sync.WaitGroup
 	wg.Add(2)
 
 	db.cleanTicker = time.NewTicker(60 * time.Second)
 	go func() {
-		for range db.cleanTicker.C {
-			db.Clean()
+		defer wg.Done()
+		for {
+			select {
+			case <-db.done:
+				return
+			case <-db.cleanTicker.C:
+				db.Clean()
+			}
 		}
-		wg.Done()
 	}()
 
 	go func() {
-		for s := range db.gc {
+		defer wg.Done()
+		for {
+			var s *Spore
+			select {
+			case <-db.done:
+				return
+			case s = <-db.gc:
+			}
+
 			// Delete expired Spore
 			db.stagingMutex.Lock()
-			delete(db.staging, s.Uuid)
+			expired := db.removeStaging(s.Uuid) != nil
 			db.stagingMutex.Unlock()
 
+			if expired {
+				db.expire(s)
+			}
+
 			// Lock the whole block for access to waiting list
 			db.waitingMutex.Lock()
 
-			for k, v := range db.waiting {
+			// Only waiting spores sharing keys with the released one, or depending on it, may be unlocked
+			waiting := db.dependencyIndex.dependents(s, db.waitingIndex.related(s))
+			db.sortByPriority(waiting)
+
+			var promoted []string
+			for _, v := range waiting {
 				err := db.CanEndorse(v.spore)
 				if err == nil {
-					db.executeEndorsement(v.spore)
-					delete(db.waiting, k)
-				} else if err == ErrDeadlineExpired {
-					delete(db.waiting, k)
+					db.executeEndorsement(v.spore, v.endorsements)
+					db.removeWaiting(v.spore.Uuid)
+					promoted = append(promoted, v.spore.Uuid)
+				} else if err == ErrDeadlineExpired || err == ErrFailedDependency {
+					db.removeWaiting(v.spore.Uuid)
 				}
 			}
 
 			db.waitingMutex.Unlock()
+
+			// Endorsements received during the promotion may have been pooled
+			for _, uuid := range promoted {
+				db.adoptOrphans(uuid)
+			}
 		}
-		wg.Done()
 	}()
 
 	if blocking {
@@ -150,31 +320,75 @@ func (db *DB) Start(blocking bool) {
 
 // Stop asks the database to be gracefully stopped.
 func (db *DB) Stop() {
-	close(db.gc)
+	close(db.done)
 	db.cleanTicker.Stop()
 }
 
+// release re-evaluates the waiting spores related to the spore, which left
+// the staging list. It blocks until the spore is handled, or the database stopped.
+func (db *DB) release(s *Spore) {
+	select {
+	case db.gc <- s:
+	case <-db.done:
+	}
+}
+
+// ErrNotFound is returned, with version.NoVersion, when getting a missing key.
+var ErrNotFound = errors.New("the requested key does not exist")
+
 // Get returns the currently stored data for the provided key.
 func (db *DB) Get(key string) ([]byte, *version.V, error) {
-	return db.Store.Get(key)
+	value, v, err := db.Store.Get(key)
+	if err != nil && v == version.NoVersion {
+		err = ErrNotFound
+	}
+
+	return value, v, err
 }
 
 // Apply directly applies the Spore's operations to the database (atomic).
 func (db *DB) Apply(s *Spore) error {
+	policies := db.getPolicies(s)
+	if policies == nil {
+		return ErrUnknownPolicy
+	}
+
+	return db.apply(s, policies)
+}
+
+// apply applies the Spore's operations under the policies it has been endorsed with.
+func (db *DB) apply(s *Spore, policies []*Policy) error {
 	db.Store.Lock()
 	defer db.Store.Unlock()
 
 	db.appliedMutex.Lock()
 	defer db.appliedMutex.Unlock()
 
-	policy := db.policies[s.Policy]
-	ok, unixTime := s.checkGracePeriod(policy.GracePeriod)
-	if !ok {
-		zap.L().Warn("Grace period expired",
-			zap.String("uuid", s.Uuid),
-			zap.Time("death", unixTime),
-		)
-		return ErrGracePeriodExpired
+	if s.untilNotBefore() > 0 {
+		return ErrNotBefore
+	}
+
+	// The shortest grace period of the involved policies applies
+	var unixTime time.Time
+	for i, policy := range policies {
+		ok, death := s.checkGracePeriod(policy.GracePeriod)
+		if !ok {
+			zap.L().Warn("Grace period expired",
+				zap.String("uuid", s.Uuid),
+				zap.String("policy", policy.Uuid),
+				zap.Time("death", death),
+			)
+			return ErrGracePeriodExpired
+		}
+
+		if i == 0 || death.Before(unixTime) {
+			unixTime = death
+		}
+	}
+
+	// Spores without deadline are remembered as long as the longest spores
+	if s.Deadline == nil {
+		unixTime = time.Now().Add(DefaultMaxTimeout)
 	}
 
 	if _, ok := db.applied[s.Uuid]; ok {
@@ -185,7 +399,7 @@ func (db *DB) Apply(s *Spore) error {
 	}
 
 	values := make(map[string]*operations.Value)
-	var oldSize, newSize uint64
+	before := make(map[string][]byte)
 
 	for _, op := range s.Operations {
 		value, ok := values[op.Key]
@@ -195,7 +409,7 @@ func (db *DB) Apply(s *Spore) error {
 				return err
 			}
 
-			oldSize += uint64(len(data))
+			before[op.Key] = data
 			values[op.Key] = operations.NewValue(data)
 			value = values[op.Key]
 		}
@@ -206,25 +420,42 @@ func (db *DB) Apply(s *Spore) error {
 		}
 	}
 
-	keys := make([]string, len(values)+1)
-	rawValues := make([][]byte, len(values)+1)
-	versions := make([]*version.V, len(values)+1)
+	keys := make([]string, 0, len(values)+4)
+	rawValues := make([][]byte, 0, len(values)+4)
 
-	i := 1
 	for k, v := range values {
-		keys[i] = k
-		rawValues[i] = v.Raw
-		versions[i] = version.New(v.Raw)
-		newSize += uint64(len(v.Raw))
-		i++
+		keys = append(keys, k)
+		rawValues = append(rawValues, v.Raw)
+	}
+
+	versions := make([]*version.V, len(keys))
+	for i, v := range rawValues {
+		versions[i] = version.New(v)
+	}
+
+	split, err := s.splitValues(before, values)
+	if err != nil {
+		return err
 	}
 
-	keys[0], rawValues[0] = db.updatePolicyUsage(oldSize, newSize, s.Policy)
+	// Internal usage keys of every policy, without version
+	for _, policy := range policies {
+		pv := split[policy.Uuid]
+		k, v := db.updatePolicyUsage(pv.oldSize, pv.newSize, policy.Uuid)
+		emitterKeys, emitterValues := db.updateEmitterUsage(s, policy, pv.oldSize, pv.newSize)
+		keys = append(append(keys, k), emitterKeys...)
+		rawValues = append(append(rawValues, v), emitterValues...)
+	}
+
+	// Local application record, stored atomically with the spore's operations
+	keys = append(keys, appliedKeyPrefix+s.Uuid)
+	rawValues = append(rawValues, marshalTime(unixTime))
+	versions = append(versions, make([]*version.V, len(keys)-len(versions))...)
 
 	zap.L().Info("Apply",
 		zap.String("uuid", s.Uuid),
 	)
-	err := db.Store.SetBatch(keys, rawValues, versions)
+	err = db.Store.SetBatch(keys, rawValues, versions)
 	if err != nil {
 		zap.L().Error("Application error",
 			zap.String("uuid", s.Uuid),
@@ -234,22 +465,54 @@ func (db *DB) Apply(s *Spore) error {
 	}
 
 	db.applied[s.Uuid] = unixTime
+	db.reloadPolicies(keys[:len(values)], rawValues[:len(values)])
 	return nil
 }
 
 // Clean is periodically called to free-up memory related to old transactions.
 func (db *DB) Clean() {
 	db.appliedMutex.Lock()
-	defer db.appliedMutex.Unlock()
-
-	unixZero := time.Unix(0, 0)
 	now := time.Now()
+	var keys []string
 
+	// Spores can no longer be applied after their grace period
 	for uuid, death := range db.applied {
-		if death.After(unixZero) && death.After(now) {
+		if death.Before(now) {
 			delete(db.applied, uuid)
+			delete(db.endorsed, uuid)
+			keys = append(keys, appliedKeyPrefix+uuid)
+		}
+	}
+
+	for uuid, deadline := range db.vetoed {
+		if deadline.Before(now) {
+			delete(db.vetoed, uuid)
+			keys = append(keys, vetoedKeyPrefix+uuid)
 		}
 	}
+	db.appliedMutex.Unlock()
+
+	if len(keys) > 0 {
+		db.forget(keys...)
+	}
+
+	// Waiting spores are only re-evaluated when conflicting spores leave the
+	// staging list, expired ones must be swept here.
+	var expired []*Spore
+	db.waitingMutex.Lock()
+	for uuid, trigger := range db.waiting {
+		if !trigger.spore.checkDeadline() {
+			db.removeWaiting(uuid)
+			expired = append(expired, trigger.spore)
+		}
+	}
+	db.waitingMutex.Unlock()
+
+	// Their dependents are dropped, until the next cleaning
+	for _, s := range expired {
+		db.expire(s)
+		go db.release(s)
+	}
 }
 
 // HashSpore process one spore's hash.
@@ -260,7 +523,7 @@ func (db *DB) HashSpore(s *Spore) []byte {
 		return hash.([]byte)
 	}
 
-	newHash := hashMessage(s)
+	newHash := s.canonicalHash()
 	go db.cache.Add(s.Uuid, newHash)
 	return newHash
 }
@@ -8,8 +8,11 @@ import (
 	"time"
 
 	"github.com/awnumar/memguard"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
 	"github.com/stretchr/testify/require"
 	"gitlab.com/SporeDB/sporedb/db/drivers/boltdb"
+	"gitlab.com/SporeDB/sporedb/db/version"
 	"gitlab.com/SporeDB/sporedb/myc/sec"
 )
 
@@ -35,6 +38,12 @@ func getTestingDB(t *testing.T) (db *DB, done func()) {
 	return
 }
 
+// addTestingPolicies registers the policies in place of the none policy,
+// so that they govern every key of the test.
+func addTestingPolicies(t *testing.T, db *DB, policies ...*Policy) {
+	require.Nil(t, db.ReplacePolicies([]string{NonePolicy.Uuid}, policies))
+}
+
 func TestHashSpore(t *testing.T) {
 	db, done := getTestingDB(t)
 	defer done()
@@ -51,6 +60,50 @@ func TestDeadlineToDuration(t *testing.T) {
 	require.InDelta(t, int64(time.Second), int64(deadlineToDuration(s.Deadline)), float64(50*time.Millisecond))
 }
 
+func TestDB_Deadline(t *testing.T) {
+	db, done := getTestingDB(t)
+	defer done()
+
+	require.Nil(t, db.AddPolicy(&Policy{
+		Uuid:    "short",
+		Timeout: ptypes.DurationProto(10 * time.Second),
+		Specs:   []*OSpec{{Key: &OSpec_Regex{".*"}}},
+	}))
+
+	s := NewSpore()
+	s.Policy = "short"
+	require.Exactly(t, ErrDeadlineMissing, db.CanEndorse(s))
+
+	s.SetTimeout(time.Minute)
+	require.Exactly(t, ErrDeadlineTooFar, db.CanEndorse(s))
+
+	s.SetTimeout(10 * time.Second)
+	require.Nil(t, db.CanEndorse(s))
+
+	s.SetDelay(time.Hour)
+	require.Exactly(t, ErrNotBeforeTooFar, db.CanEndorse(s))
+
+	s.SetDelay(DefaultMaxDelay)
+	require.Nil(t, db.CanEndorse(s), "policies without max delay must allow DefaultMaxDelay")
+
+	s.Policy = "none"
+	s.SetTimeout(time.Minute)
+	require.Nil(t, db.CanEndorse(s), "policies without timeout must allow DefaultMaxTimeout")
+
+	timeout, err := db.SporeTimeout("short", 0)
+	require.Nil(t, err)
+	require.Exactly(t, DefaultTimeout, timeout)
+
+	timeout, _ = db.SporeTimeout("short", time.Hour)
+	require.Exactly(t, 10*time.Second, timeout)
+
+	timeout, _ = db.SporeTimeout("none", time.Hour)
+	require.Exactly(t, DefaultMaxTimeout, timeout)
+
+	_, err = db.SporeTimeout("unknown", 0)
+	require.Exactly(t, ErrUnknownPolicy, err)
+}
+
 func TestDB_Single_Quorum1(t *testing.T) {
 	db, done := getTestingDB(t)
 	defer done()
@@ -180,3 +233,868 @@ func TestDB_Single_Quorum2(t *testing.T) {
 	db.stagingMutex.RUnlock()
 	db.waitingMutex.RUnlock()
 }
+
+// getTestingEndorsers creates the keyrings of other endorsers, trusted by db.
+// The returned endorsers include the local one.
+func getTestingEndorsers(t *testing.T, db *DB, identities ...string) (map[string]*sec.KeyRingEd25519, []*Endorser) {
+	password, _ := memguard.NewFromBytes([]byte("password"), true)
+	defer password.Destroy()
+
+	pub, _, _ := db.KeyRing.GetPublic("")
+	endorsers := []*Endorser{{Public: pub}}
+	keyRings := make(map[string]*sec.KeyRingEd25519)
+	for _, identity := range identities {
+		k := sec.NewKeyRingEd25519()
+		require.Nil(t, k.CreatePrivate(password))
+		p, _, _ := k.GetPublic("")
+		require.Nil(t, db.KeyRing.AddPublic(identity, sec.TrustHIGH, p))
+		endorsers = append(endorsers, &Endorser{Public: p})
+		keyRings[identity] = k
+	}
+
+	return keyRings, endorsers
+}
+
+func TestDB_ConflictResolution(t *testing.T) {
+	db, done := getTestingDB(t)
+	defer done()
+
+	keyRings, endorsers := getTestingEndorsers(t, db, "e1", "e2", "e3")
+	addTestingPolicies(t, db, &Policy{
+		Uuid:      "conflicts",
+		Quorum:    3,
+		Endorsers: endorsers,
+		Specs:     []*OSpec{{Key: &OSpec_Regex{".*"}}},
+	})
+	db.Start(false)
+
+	high, low := getTestPolicySpore(db, "conflicts", setOp("key", "high")), getTestPolicySpore(db, "conflicts", setOp("key", "low"))
+	if db.higherPriority(low, high) {
+		high, low = low, high
+	}
+
+	// Low priority spore is staged first, the other one must wait
+	require.Nil(t, db.Endorse(low))
+	require.Nil(t, db.Endorse(high))
+	require.IsType(t, &Endorsement{}, <-db.Messages)
+	db.waitingMutex.RLock()
+	require.NotNil(t, db.waiting[high.Uuid])
+	db.waitingMutex.RUnlock()
+
+	// First opponent: low priority spore may still reach its quorum
+	require.Nil(t, endorseAs(db, keyRings["e1"], "e1", high))
+	db.stagingMutex.RLock()
+	require.NotNil(t, db.staging[low.Uuid])
+	db.stagingMutex.RUnlock()
+
+	// Second opponent: low priority spore is dead, and is vetoed
+	require.Nil(t, endorseAs(db, keyRings["e2"], "e2", high))
+	veto, ok := (<-db.Messages).(*Veto)
+	require.True(t, ok)
+	require.Exactly(t, low.Uuid, veto.Uuid)
+	require.Nil(t, db.KeyRing.Verify("", vetoHash(db.HashSpore(low)), veto.Signature))
+	require.Exactly(t, ErrVetoed, db.Endorse(low))
+
+	// High priority spore is then endorsed locally, and applied
+	require.IsType(t, &Endorsement{}, <-db.Messages)
+	time.Sleep(10 * time.Millisecond)
+
+	value, _, err := db.Get("key")
+	require.Nil(t, err)
+	require.Exactly(t, high.Operations[0].Data, value)
+}
+
+func TestSporeIndex(t *testing.T) {
+	newTrigger := func(keys ...string) *dbTrigger {
+		s := NewSpore()
+		for _, k := range keys {
+			s.Operations = append(s.Operations, &Operation{Key: k, Op: Operation_SET})
+		}
+		return &dbTrigger{spore: s}
+	}
+
+	a, b, c := newTrigger("k1", "k2"), newTrigger("k2", "k3"), newTrigger("k4")
+	index := make(sporeIndex)
+	index.add(a)
+	index.add(b)
+	index.add(c)
+
+	require.Exactly(t, []*dbTrigger{b}, index.related(a.spore))
+	require.Len(t, index.related(newTrigger("k1", "k2", "k3").spore), 2)
+	require.Empty(t, index.related(c.spore))
+
+	index.remove(b)
+	require.Empty(t, index.related(a.spore))
+	require.Len(t, index, 3)
+}
+
+func TestDB_RestoreState(t *testing.T) {
+	db, done := getTestingDB(t)
+	defer done()
+
+	pub, _, _ := db.KeyRing.GetPublic("")
+	promises := &Policy{
+		Uuid:      "promises",
+		Quorum:    2,
+		Endorsers: []*Endorser{{Public: pub}},
+		Claims:    []string{"a"},
+		Specs:     []*OSpec{{Key: &OSpec_Regex{"^a"}}},
+	}
+	require.Nil(t, db.AddPolicy(promises))
+
+	staged, applied := getTestPolicySpore(db, "promises", setOp("a", "staged")), getTestPolicySpore(db, "none", setOp("b", "applied"))
+	require.Nil(t, db.Endorse(staged))
+	require.Nil(t, db.Endorse(applied))
+
+	// Restart the database on the same store
+	db2 := NewDB(db.Store, db.Identity, db.KeyRing)
+	require.Nil(t, db2.AddPolicy(NonePolicy))
+	require.Nil(t, db2.AddPolicy(promises))
+	db2.Start(false)
+
+	db2.stagingMutex.RLock()
+	require.NotNil(t, db2.staging[staged.Uuid])
+	require.Len(t, db2.staging[staged.Uuid].endorsements, 1)
+	db2.stagingMutex.RUnlock()
+
+	require.Exactly(t, ErrConflictingWithStaging, db2.CanEndorse(getTestPolicySpore(db, "promises", setOp("a", "conflicting"))))
+	require.Exactly(t, ErrDuplicatedApplication, db2.apply(applied, []*Policy{NonePolicy}))
+
+	// The promise is forgotten once the spore leaves the staging list
+	time.Sleep(1100 * time.Millisecond)
+	db3 := NewDB(db.Store, db.Identity, db.KeyRing)
+	require.Nil(t, db3.AddPolicy(promises))
+	require.Nil(t, db3.restore())
+	require.Empty(t, db3.staging)
+}
+
+func TestDB_Clean(t *testing.T) {
+	db, done := getTestingDB(t)
+	defer done()
+	db.Start(false)
+
+	s := NewSpore()
+	s.Operations = []*Operation{{Key: "a", Op: Operation_SET, Data: []byte("A")}}
+	require.Nil(t, db.Apply(s))
+
+	db.appliedMutex.Lock()
+	require.True(t, db.applied[s.Uuid].After(time.Now()), "spores without deadline must be remembered for a while")
+	db.applied[s.Uuid] = time.Now().Add(-time.Second)
+	db.appliedMutex.Unlock()
+
+	db.Clean()
+	require.Exactly(t, SporeStatus_UNKNOWN, db.Status(s.Uuid))
+
+	// Spores leaving the staging list after the stop must not panic
+	db.Stop()
+	db.release(s)
+}
+
+func TestDB_Equivocation(t *testing.T) {
+	db, done := getTestingDB(t)
+	defer done()
+
+	delay := EquivocationDelay
+	EquivocationDelay = 10 * time.Millisecond
+	defer func() { EquivocationDelay = delay }()
+
+	keyRings, endorsers := getTestingEndorsers(t, db, "e1", "e2", "e3")
+	addTestingPolicies(t, db, &Policy{
+		Uuid:      "equivocation",
+		Quorum:    3,
+		Endorsers: endorsers,
+		Specs:     []*OSpec{{Key: &OSpec_Regex{".*"}}},
+	})
+	db.Start(false)
+
+	a, b := getTestPolicySpore(db, "equivocation", setOp("key", "a")), getTestPolicySpore(db, "equivocation", setOp("key", "b"))
+	require.Nil(t, db.Endorse(a))
+	require.Nil(t, db.Endorse(b))
+	require.IsType(t, &Endorsement{}, <-db.Messages)
+
+	require.Nil(t, endorseAs(db, keyRings["e1"], "e1", a))
+	require.Nil(t, endorseAs(db, keyRings["e1"], "e1", b))
+
+	evidence, ok := (<-db.Messages).(*Evidence)
+	require.True(t, ok)
+	require.Exactly(t, "e1", evidence.Emitter)
+	require.Exactly(t, ErrDuplicatedEvidence, db.AddEvidence(evidence))
+
+	evidences, err := db.Evidences()
+	require.Nil(t, err)
+	require.Len(t, evidences, 1)
+
+	evidence.Second = getTestPolicySpore(db, "equivocation", setOp("key", "forged"))
+	require.NotNil(t, db.AddEvidence(evidence), "forged evidences must be rejected")
+
+	db.Exclude("e3")
+	require.Exactly(t, ErrExcludedEndorser, endorseAs(db, keyRings["e3"], "e3", a))
+}
+
+func TestDB_CosignedEquivocation(t *testing.T) {
+	db, done := getTestingDB(t)
+	defer done()
+
+	delay := EquivocationDelay
+	EquivocationDelay = 10 * time.Millisecond
+	defer func() { EquivocationDelay = delay }()
+
+	keyRings, endorsers := getTestingEndorsers(t, db, "e1", "e2", "c1", "c2")
+	addTestingPolicies(t, db, &Policy{
+		Uuid:      "cosigned",
+		Quorum:    3,
+		Endorsers: endorsers[:3],
+		Specs:     []*OSpec{{Key: &OSpec_Regex{".*"}, Cosigners: endorsers[3:]}},
+	})
+	db.Start(false)
+
+	// The cosignatures are not sorted by emitter
+	newSpore := func(data string) *Spore {
+		s := getTestPolicySpore(db, "cosigned", setOp("key", data))
+		for _, identity := range []string{"c2", "c1"} {
+			signature, err := keyRings[identity].Sign(s.signingHash())
+			require.Nil(t, err)
+			s.Cosignatures = append(s.Cosignatures, &Cosignature{Emitter: identity, Signature: signature})
+		}
+		return s
+	}
+
+	a, b := newSpore("a"), newSpore("b")
+	require.Nil(t, db.Endorse(a))
+	require.Nil(t, db.Endorse(b))
+	require.IsType(t, &Endorsement{}, <-db.Messages)
+
+	require.Nil(t, endorseAs(db, keyRings["e1"], "e1", a))
+	require.Nil(t, endorseAs(db, keyRings["e1"], "e1", b))
+
+	evidence, ok := (<-db.Messages).(*Evidence)
+	require.True(t, ok)
+	require.Exactly(t, ErrDuplicatedEvidence, db.AddEvidence(evidence))
+}
+
+func TestDB_OrphanEndorsements(t *testing.T) {
+	db, done := getTestingDB(t)
+	defer done()
+
+	keyRings, endorsers := getTestingEndorsers(t, db, "e1")
+	addTestingPolicies(t, db, &Policy{
+		Uuid:      "orphans",
+		Quorum:    2,
+		Endorsers: endorsers,
+		Specs:     []*OSpec{{Key: &OSpec_Regex{".*"}}},
+	})
+	db.Start(false)
+
+	s := getTestPolicySpore(db, "orphans", setOp("key", "orphan"))
+
+	signature, _ := keyRings["e1"].Sign(hashMessage(s))
+	e := &Endorsement{Emitter: "e1", Uuid: s.Uuid, Signature: signature}
+	require.Exactly(t, ErrNoRelatedSpore, db.AddEndorsement(e))
+	require.Exactly(t, ErrNoRelatedSpore, db.AddEndorsement(e))
+	require.False(t, db.Knows(s.Uuid))
+
+	// The buffered endorsement completes the quorum once the spore arrives
+	require.Nil(t, db.Endorse(s))
+	time.Sleep(10 * time.Millisecond)
+	require.True(t, db.Knows(s.Uuid))
+
+	value, _, err := db.Get("key")
+	require.Nil(t, err)
+	require.Exactly(t, []byte("orphan"), value)
+}
+
+func TestDB_StalledSpore(t *testing.T) {
+	db, done := getTestingDB(t)
+	defer done()
+	db.StallInterval = 10 * time.Millisecond
+
+	keyRings, endorsers := getTestingEndorsers(t, db, "e1")
+	addTestingPolicies(t, db, &Policy{
+		Uuid:      "stalled",
+		Quorum:    2,
+		Endorsers: endorsers,
+		Specs:     []*OSpec{{Key: &OSpec_Regex{".*"}}},
+	})
+	db.Start(false)
+
+	s := getTestPolicySpore(db, "stalled", setOp("key", "stalled"))
+
+	require.Nil(t, db.Endorse(s))
+	require.IsType(t, &Endorsement{}, <-db.Messages)
+	require.Len(t, db.Endorsements(s.Uuid), 1)
+
+	// Missing endorsements are requested until the spore settles
+	for i := 0; i < 2; i++ {
+		request, ok := (<-db.Messages).(*EndorsementRequest)
+		require.True(t, ok)
+		require.Exactly(t, s.Uuid, request.Uuid)
+	}
+
+	require.Nil(t, endorseAs(db, keyRings["e1"], "e1", s))
+	time.Sleep(50 * time.Millisecond)
+	require.Len(t, db.Endorsements(s.Uuid), 2, "endorsements of applied spores must be kept")
+
+	for len(db.Messages) > 0 { // requests sent before the application
+		<-db.Messages
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	select {
+	case m := <-db.Messages:
+		require.Fail(t, "unexpected message after application", "%v", m)
+	default:
+	}
+}
+
+func TestDB_AppliedEndorsements(t *testing.T) {
+	db, done := getTestingDB(t)
+	defer done()
+	stalled, done2 := getTestingDB(t)
+	defer done2()
+	stalled.KeyRing = db.KeyRing
+
+	keyRings, endorsers := getTestingEndorsers(t, db, "e1")
+	policy := &Policy{
+		Uuid:      "applied",
+		Quorum:    2,
+		Endorsers: endorsers,
+		Specs:     []*OSpec{{Key: &OSpec_Regex{".*"}}},
+	}
+	addTestingPolicies(t, db, policy)
+	addTestingPolicies(t, stalled, policy)
+	db.Start(false)
+	stalled.Start(false)
+
+	s := getTestPolicySpore(db, "applied", setOp("key", "applied"))
+
+	// The responder applied the spore, while the stalled node missed the endorsement of e1
+	require.Nil(t, db.Endorse(s))
+	require.Nil(t, endorseAs(db, keyRings["e1"], "e1", s))
+	require.Nil(t, stalled.Endorse(s))
+	time.Sleep(10 * time.Millisecond)
+	require.Exactly(t, SporeStatus_APPLIED, db.Status(s.Uuid))
+	require.Exactly(t, SporeStatus_STAGED, stalled.Status(s.Uuid))
+
+	for _, e := range db.Endorsements(s.Uuid) {
+		_ = stalled.AddEndorsement(e)
+	}
+	time.Sleep(10 * time.Millisecond)
+	require.Exactly(t, SporeStatus_APPLIED, stalled.Status(s.Uuid))
+
+	// Endorsements are forgotten with the application record
+	db.appliedMutex.Lock()
+	db.applied[s.Uuid] = time.Now().Add(-time.Second)
+	db.appliedMutex.Unlock()
+	db.Clean()
+	require.Nil(t, db.Endorsements(s.Uuid))
+}
+
+func TestDB_Validity(t *testing.T) {
+	db, done := getTestingDB(t)
+	defer done()
+
+	// Spores with a validity are signed without deadline
+	s := NewSpore()
+	s.Emitter = db.Identity
+	s.Operations = []*Operation{setOp("key", "valid")}
+	s.SetValidity(time.Minute)
+	require.Nil(t, s.Sign(db.KeyRing))
+	require.Exactly(t, ErrDeadlineMissing, db.Endorse(s))
+
+	// The deadline is set afterwards, up to the validity
+	s.NarrowDeadline(time.Hour)
+	require.True(t, proto.Equal(s.ValidUntil, s.Deadline))
+	s.SetTimeout(2 * time.Minute)
+	require.Exactly(t, ErrDeadlineBeyondValidity, db.Endorse(s))
+
+	s.NarrowDeadline(time.Second)
+	require.Nil(t, db.Endorse(s))
+	value, _, err := db.Get("key")
+	require.Nil(t, err)
+	require.Exactly(t, []byte("valid"), value)
+}
+
+func TestDB_Cancel(t *testing.T) {
+	db, done := getTestingDB(t)
+	defer done()
+
+	keyRings, endorsers := getTestingEndorsers(t, db, "e1")
+	addTestingPolicies(t, db, &Policy{
+		Uuid:      "cancel",
+		Quorum:    2,
+		Endorsers: endorsers,
+		Specs:     []*OSpec{{Key: &OSpec_Regex{".*"}}},
+	})
+	db.Start(false)
+
+	// The mistaken spore is staged, the follow-up must wait
+	mistaken, followUp := getTestPolicySpore(db, "cancel", setOp("key", "mistaken")), getTestPolicySpore(db, "cancel", setOp("key", "follow-up"))
+	require.Nil(t, db.Endorse(mistaken))
+	require.Nil(t, db.Endorse(followUp))
+	require.IsType(t, &Endorsement{}, <-db.Messages)
+
+	// Only the emitter of the spore may cancel it
+	signature, _ := keyRings["e1"].Sign(cancelHash(db.HashSpore(mistaken)))
+	require.NotNil(t, db.AddCancel(&Cancel{Uuid: mistaken.Uuid, Signature: signature}))
+	require.Exactly(t, ErrNoRelatedSpore, db.Cancel("unknown"))
+
+	// Our endorsement is withdrawn, and the follow-up is endorsed
+	require.Nil(t, db.Cancel(mistaken.Uuid))
+	var veto, cancel, endorsement bool
+	for i := 0; i < 3; i++ {
+		switch m := (<-db.Messages).(type) {
+		case *Veto:
+			veto = m.Uuid == mistaken.Uuid
+		case *Cancel:
+			cancel = m.Uuid == mistaken.Uuid
+		case *Endorsement:
+			endorsement = m.Uuid == followUp.Uuid
+		}
+	}
+	require.True(t, veto && cancel && endorsement)
+	require.Exactly(t, ErrVetoed, db.Endorse(mistaken))
+	require.Exactly(t, ErrNoRelatedSpore, db.Cancel(mistaken.Uuid))
+
+	require.Nil(t, endorseAs(db, keyRings["e1"], "e1", followUp))
+	time.Sleep(10 * time.Millisecond)
+
+	value, _, err := db.Get("key")
+	require.Nil(t, err)
+	require.Exactly(t, []byte("follow-up"), value)
+
+	// Cancellations received before their spore are checked on its endorsement
+	early := getTestPolicySpore(db, "cancel", setOp("key", "early"))
+	forged, _ := keyRings["e1"].Sign(cancelHash(db.HashSpore(early)))
+	signature, _ = db.KeyRing.Sign(cancelHash(db.HashSpore(early)))
+	require.Exactly(t, ErrNoRelatedSpore, db.AddCancel(&Cancel{Uuid: early.Uuid, Signature: forged}))
+	require.Exactly(t, ErrNoRelatedSpore, db.AddCancel(&Cancel{Uuid: early.Uuid, Signature: signature}))
+	require.Exactly(t, ErrVetoed, db.Endorse(early))
+	require.Exactly(t, &Cancel{Uuid: early.Uuid, Signature: signature}, <-db.Messages)
+	require.Exactly(t, SporeStatus_DROPPED, db.Status(early.Uuid))
+
+	// Cancellations of committed spores are relayed once
+	late := getTestPolicySpore(db, "cancel", setOp("key", "late"))
+	late.SetDelay(200 * time.Millisecond)
+	signTestSpore(db, late)
+	require.Nil(t, db.Endorse(late))
+	require.IsType(t, &Endorsement{}, <-db.Messages)
+	require.Nil(t, endorseAs(db, keyRings["e1"], "e1", late))
+	require.Exactly(t, SporeStatus_COMMITTED, db.Status(late.Uuid))
+
+	require.Exactly(t, ErrAlreadyCommitted, db.Cancel(late.Uuid))
+	c, ok := (<-db.Messages).(*Cancel)
+	require.True(t, ok)
+	require.Exactly(t, ErrDuplicatedCancel, db.AddCancel(c))
+
+	time.Sleep(300 * time.Millisecond)
+	value, _, _ = db.Get("key")
+	require.Exactly(t, []byte("late"), value)
+}
+
+func TestDB_Dependencies(t *testing.T) {
+	db, done := getTestingDB(t)
+	defer done()
+
+	keyRings, endorsers := getTestingEndorsers(t, db, "e1")
+	addTestingPolicies(t, db, &Policy{
+		Uuid:      "dependencies",
+		Quorum:    2,
+		Endorsers: endorsers,
+		Specs:     []*OSpec{{Key: &OSpec_Regex{"^[a-d]$"}}},
+	}, &Policy{
+		Uuid:  "free",
+		Specs: []*OSpec{{Key: &OSpec_Regex{"^free:"}}},
+	})
+	db.Start(false)
+
+	newSpore := func(key string, dependencies ...string) *Spore {
+		s := getTestPolicySpore(db, "dependencies", &Operation{Key: key, Op: Operation_CONCAT, Data: []byte(key)})
+		s.Dependencies = dependencies
+		signTestSpore(db, s)
+		return s
+	}
+
+	// The child is parked until its parent is applied
+	parent := newSpore("a")
+	child := newSpore("b", parent.Uuid)
+	require.Nil(t, db.Endorse(child))
+	require.Nil(t, db.Endorse(parent))
+	require.Len(t, db.Endorsements(child.Uuid), 0)
+
+	require.Nil(t, endorseAs(db, keyRings["e1"], "e1", parent))
+	time.Sleep(50 * time.Millisecond)
+	require.Len(t, db.Endorsements(child.Uuid), 1)
+	require.Nil(t, endorseAs(db, keyRings["e1"], "e1", child))
+	time.Sleep(50 * time.Millisecond)
+
+	value, _, err := db.Get("b")
+	require.Nil(t, err)
+	require.Exactly(t, []byte("b"), value)
+
+	// The child is dropped if its parent is cancelled
+	parent = newSpore("c")
+	child = newSpore("d", parent.Uuid)
+	require.Nil(t, db.Endorse(parent))
+	require.Nil(t, db.Endorse(child))
+	require.Nil(t, db.Cancel(parent.Uuid))
+	time.Sleep(50 * time.Millisecond)
+
+	require.False(t, db.Knows(child.Uuid))
+	require.Exactly(t, ErrFailedDependency, db.Endorse(child))
+
+	// Spores needing no endorsement release their dependents too
+	parent = getTestPolicySpore(db, "free", setOp("free:a", "a"))
+	child = getTestPolicySpore(db, "free", setOp("free:b", "b"))
+	child.Dependencies = []string{parent.Uuid}
+	signTestSpore(db, child)
+	require.Nil(t, db.Endorse(child))
+	require.Exactly(t, SporeStatus_WAITING, db.Status(child.Uuid))
+	require.Nil(t, db.Endorse(parent))
+	time.Sleep(50 * time.Millisecond)
+
+	value, _, err = db.Get("free:b")
+	require.Nil(t, err)
+	require.Exactly(t, []byte("b"), value)
+}
+
+func TestDB_NotBefore(t *testing.T) {
+	db, done := getTestingDB(t)
+	defer done()
+
+	keyRings, endorsers := getTestingEndorsers(t, db, "e1")
+	addTestingPolicies(t, db, &Policy{
+		Uuid:      "scheduled",
+		Quorum:    2,
+		Endorsers: endorsers,
+		Specs:     []*OSpec{{Key: &OSpec_Regex{".*"}}},
+	})
+	db.Start(false)
+
+	newSpore := func(data string) *Spore {
+		s := getTestPolicySpore(db, "scheduled", setOp("key", data))
+		s.SetDelay(200 * time.Millisecond)
+		signTestSpore(db, s)
+		return s
+	}
+
+	scheduled, conflicting := newSpore("scheduled"), newSpore("conflicting")
+	require.Exactly(t, ErrNotBefore, db.Apply(scheduled))
+	require.Nil(t, db.Endorse(scheduled))
+	require.Nil(t, endorseAs(db, keyRings["e1"], "e1", scheduled))
+
+	// Once the quorum is reached, the spore cannot be cancelled anymore
+	require.Exactly(t, ErrAlreadyCommitted, db.Cancel(scheduled.Uuid))
+
+	// The endorsed spore keeps its promise until its application
+	require.Nil(t, db.Endorse(conflicting))
+	time.Sleep(50 * time.Millisecond)
+	require.Len(t, db.Endorsements(scheduled.Uuid), 2)
+	_, _, err := db.Get("key")
+	require.NotNil(t, err)
+
+	time.Sleep(250 * time.Millisecond)
+	value, _, err := db.Get("key")
+	require.Nil(t, err)
+	require.Exactly(t, []byte("scheduled"), value)
+	require.Len(t, db.Endorsements(conflicting.Uuid), 1)
+}
+
+func TestDB_NotBeforeWithoutEndorsement(t *testing.T) {
+	db, done := getTestingDB(t)
+	defer done()
+	db.Start(false)
+
+	s, sign := getTestSpore(db)
+	s.SetTimeout(time.Second)
+	s.SetDelay(50 * time.Millisecond)
+	s.Operations = []*Operation{{Key: "key", Op: Operation_SET, Data: []byte("scheduled")}}
+	sign()
+
+	// The spore is tracked as committed until its not-before time
+	require.Nil(t, db.Endorse(s))
+	time.Sleep(10 * time.Millisecond)
+	require.Exactly(t, SporeStatus_COMMITTED, db.Status(s.Uuid))
+
+	time.Sleep(100 * time.Millisecond)
+	require.Exactly(t, SporeStatus_APPLIED, db.Status(s.Uuid))
+	value, _, err := db.Get("key")
+	require.Nil(t, err)
+	require.Exactly(t, []byte("scheduled"), value)
+}
+
+func TestSpore_GracePeriod(t *testing.T) {
+	s := NewSpore()
+	s.SetTimeout(-5 * time.Second)
+	ok, _ := s.checkGracePeriod(nil)
+	require.False(t, ok)
+
+	// The grace period starts at the not-before time if later than the deadline
+	s.SetDelay(5 * time.Second)
+	ok, _ = s.checkGracePeriod(nil)
+	require.True(t, ok)
+}
+
+func TestDB_MultiPolicy(t *testing.T) {
+	db, done := getTestingDB(t)
+	defer done()
+
+	keyRings, endorsers := getTestingEndorsers(t, db, "e1", "e2")
+	addTestingPolicies(t, db, &Policy{
+		Uuid:      "left",
+		Quorum:    2,
+		Endorsers: endorsers[:2],
+		Specs:     []*OSpec{{Key: &OSpec_Regex{"^left:"}}},
+	})
+	addTestingPolicies(t, db, &Policy{
+		Uuid:      "right",
+		Quorum:    1,
+		Endorsers: endorsers[2:],
+		Specs:     []*OSpec{{Key: &OSpec_Regex{"^right:"}}},
+	})
+	db.Start(false)
+
+	s, sign := getTestSpore(db)
+	s.SetTimeout(time.Second)
+	s.Policy = "left"
+	s.Operations = []*Operation{
+		{Key: "left:a", Op: Operation_SET, Data: []byte("a")},
+		{Key: "right:b", Op: Operation_SET, Data: []byte("b"), Policy: "right"},
+	}
+	sign()
+
+	// Each operation is checked against its own policy, which must be listed
+	require.Exactly(t, ErrOpPolicyNotInvolved, db.CanEndorse(s))
+	s.Policies = []string{"right"}
+	s.Operations[1].Policy = ""
+	require.Exactly(t, ErrOpDisabledKey, db.CanEndorse(s))
+	s.Operations[1].Policy = "right"
+	sign()
+
+	// The quorum of every policy is required
+	require.Nil(t, db.Endorse(s))
+	require.Nil(t, endorseAs(db, keyRings["e1"], "e1", s))
+	time.Sleep(10 * time.Millisecond)
+	require.Len(t, db.Endorsements(s.Uuid), 2)
+
+	require.Nil(t, endorseAs(db, keyRings["e2"], "e2", s))
+	time.Sleep(10 * time.Millisecond)
+	require.True(t, db.isDone(s.Uuid))
+
+	for key, value := range map[string]string{"left:a": "a", "right:b": "b"} {
+		data, _, err := db.Get(key)
+		require.Nil(t, err)
+		require.Exactly(t, []byte(value), data)
+	}
+
+	// Sizes are accounted per policy
+	usage, _ := db.getCurrentPolicyUsage("left")
+	require.Exactly(t, uint64(1), usage)
+	usage, _ = db.getCurrentPolicyUsage("right")
+	require.Exactly(t, uint64(1), usage)
+}
+
+func TestDB_Cosignatures(t *testing.T) {
+	db, done := getTestingDB(t)
+	defer done()
+
+	keyRings, endorsers := getTestingEndorsers(t, db, "e1", "e2")
+	addTestingPolicies(t, db, &Policy{
+		Uuid:      "swap",
+		Quorum:    1,
+		Endorsers: endorsers[:1],
+		Specs: []*OSpec{
+			{Key: &OSpec_Regex{"^free:"}},
+			{Key: &OSpec_Regex{"^swap:"}, Cosigners: endorsers[:2]},
+			{Key: &OSpec_Regex{"^vote:"}, Cosigners: endorsers[1:], CosignQuorum: 1},
+		},
+	})
+	db.Start(false)
+
+	s := NewSpore()
+	s.SetTimeout(time.Minute)
+	s.Policy = "swap"
+	s.Emitter = db.Identity
+	s.Operations = []*Operation{{Key: "free:a", Op: Operation_SET, Data: []byte("a")}}
+	require.Nil(t, db.CanEndorse(s))
+
+	// The emitter's signature counts, but e1 must cosign
+	s.Operations = append(s.Operations, &Operation{Key: "swap:a", Op: Operation_SET, Data: []byte("a")})
+	require.Exactly(t, ErrMissingCosignature, db.CanEndorse(s))
+
+	cosign := func(identity string) {
+		signature, err := keyRings[identity].Sign(s.signingHash())
+		require.Nil(t, err)
+		s.Cosignatures = append(s.Cosignatures, &Cosignature{Emitter: identity, Signature: signature})
+	}
+
+	cosign("e2")
+	require.Exactly(t, ErrMissingCosignature, db.CanEndorse(s))
+	cosign("e1")
+	require.Nil(t, db.CanEndorse(s))
+
+	// Relays may reorder the cosignatures without changing the hash of the spore
+	hash := s.canonicalHash()
+	s.Cosignatures[0], s.Cosignatures[1] = s.Cosignatures[1], s.Cosignatures[0]
+	require.Exactly(t, hash, s.canonicalHash())
+	require.Nil(t, s.Cosign("e2", keyRings["e2"]))
+	require.Exactly(t, "e1", s.Cosignatures[0].Emitter)
+	require.Exactly(t, hash, s.canonicalHash())
+
+	// One of e1 and e2 is enough
+	s.Operations = append(s.Operations, &Operation{Key: "vote:a", Op: Operation_SET, Data: []byte("a")})
+	s.Cosignatures = nil
+	cosign("e1")
+	cosign("e1")
+	require.Exactly(t, ErrDuplicatedCosignature, db.CanEndorse(s))
+	s.Cosignatures = s.Cosignatures[1:]
+	require.Nil(t, db.CanEndorse(s))
+
+	// Cosignatures are bound to the spore, which is then signed by its emitter
+	s.Operations[0].Data = []byte("b")
+	require.NotNil(t, db.CanEndorse(s))
+	s.Cosignatures = nil
+	cosign("e1")
+	require.Nil(t, db.Cosign(s)) // no-op for the emitter
+	require.Len(t, s.Cosignatures, 1)
+
+	require.Nil(t, db.Submit(s))
+	time.Sleep(10 * time.Millisecond)
+	data, _, err := db.Get("swap:a")
+	require.Nil(t, err)
+	require.Exactly(t, []byte("a"), data)
+}
+
+func TestDB_SubmitSigned(t *testing.T) {
+	db, done := getTestingDB(t)
+	defer done()
+
+	keyRings, _ := getTestingEndorsers(t, db, "alice", "mallory")
+	db.Start(false)
+
+	s := NewSpore()
+	s.SetTimeout(time.Minute)
+	s.Operations = []*Operation{{Key: "a", Op: Operation_SET, Data: []byte("a")}}
+
+	require.Nil(t, s.Sign(keyRings["alice"]))
+	require.Exactly(t, ErrNoEmitter, db.SubmitSigned(s))
+
+	s.Emitter = "alice"
+	require.Nil(t, s.Sign(keyRings["mallory"]))
+	require.NotNil(t, db.SubmitSigned(s))
+
+	require.Nil(t, s.Sign(keyRings["alice"]))
+	require.Nil(t, db.SubmitSigned(s))
+	require.Exactly(t, s, <-db.Messages)
+
+	time.Sleep(10 * time.Millisecond)
+	data, _, err := db.Get("a")
+	require.Nil(t, err)
+	require.Exactly(t, []byte("a"), data)
+}
+
+func TestDB_Status(t *testing.T) {
+	db, done := getTestingDB(t)
+	defer done()
+
+	keyRings, endorsers := getTestingEndorsers(t, db, "e1")
+	addTestingPolicies(t, db, &Policy{
+		Uuid:      "status",
+		Quorum:    2,
+		Endorsers: endorsers,
+		Specs:     []*OSpec{{Key: &OSpec_Regex{".*"}}},
+	})
+	db.Start(false)
+
+	staged, waiting := getTestPolicySpore(db, "status", setOp("key", "staged")), getTestPolicySpore(db, "status", setOp("key", "waiting"))
+	require.Exactly(t, SporeStatus_UNKNOWN, db.Status(staged.Uuid))
+	require.Nil(t, db.Endorse(staged))
+	require.Nil(t, db.Endorse(waiting))
+	require.Exactly(t, SporeStatus_STAGED, db.Status(staged.Uuid))
+	require.Exactly(t, SporeStatus_WAITING, db.Status(waiting.Uuid))
+
+	require.Nil(t, endorseAs(db, keyRings["e1"], "e1", staged))
+	time.Sleep(10 * time.Millisecond)
+	require.Exactly(t, SporeStatus_APPLIED, db.Status(staged.Uuid))
+	require.Exactly(t, ErrDuplicatedApplication, db.Endorse(staged))
+
+	require.Nil(t, db.Cancel(waiting.Uuid))
+	require.Exactly(t, SporeStatus_DROPPED, db.Status(waiting.Uuid))
+}
+
+func TestDB_Simulate(t *testing.T) {
+	db, done := getTestingDB(t)
+	defer done()
+
+	_, endorsers := getTestingEndorsers(t, db, "e1")
+	addTestingPolicies(t, db, &Policy{
+		Uuid:      "simulate",
+		Quorum:    2,
+		Endorsers: endorsers,
+		MaxSize:   10,
+		Specs:     []*OSpec{{Key: &OSpec_Regex{"^allowed:"}}},
+	})
+	db.Start(false)
+
+	r := db.Simulate(getTestPolicySpore(db, "simulate", setOp("allowed:a", "12345")))
+	require.Nil(t, r.Err())
+	require.Exactly(t, "conflicts", r.Checks[len(r.Checks)-1].Name)
+	require.Exactly(t, map[string][]byte{"allowed:a": []byte("12345")}, r.Values)
+	require.Len(t, r.Usages, 1)
+	require.Exactly(t, SimulationUsage{Policy: "simulate", NewSize: 5, MaxSize: 10, EmitterNewSize: 5}, r.Usages[0])
+
+	// Checks stop at the first failure
+	r = db.Simulate(getTestPolicySpore(db, "simulate", setOp("forbidden:a", "a")))
+	require.Exactly(t, ErrOpDisabledKey, r.Err())
+	require.Exactly(t, "operation #1 SET forbidden:a (simulate)", r.Checks[len(r.Checks)-1].Name)
+	for _, c := range r.Checks[:len(r.Checks)-1] {
+		require.Nil(t, c.Err, c.Name)
+	}
+
+	r = db.Simulate(getTestPolicySpore(db, "simulate", setOp("allowed:a", "12345678901")))
+	require.Exactly(t, ErrPolicyQuotaExceeded, r.Err())
+
+	// Conflicts name the staged spore
+	staged := getTestPolicySpore(db, "simulate", setOp("allowed:a", "a"))
+	require.Nil(t, db.Endorse(staged))
+	r = db.Simulate(getTestPolicySpore(db, "simulate", setOp("allowed:a", "b")))
+	require.Exactly(t, ErrConflictingWithStaging, r.Err())
+	require.Exactly(t, staged.Uuid, r.Conflict)
+}
+
+func TestDB_GetNotFound(t *testing.T) {
+	db, done := getTestingDB(t)
+	defer done()
+
+	value, v, err := db.Get("key")
+	require.Exactly(t, ErrNotFound, err)
+	require.Nil(t, value)
+	require.Exactly(t, version.NoVersion, v)
+}
+
+func TestDB_Requirements(t *testing.T) {
+	db, done := getTestingDB(t)
+	defer done()
+
+	// Missing keys may be required to stay missing
+	s, sign := getTestSpore(db)
+	s.Requirements = map[string]*version.V{"key": version.NoVersion}
+	s.Operations = []*Operation{{Key: "key", Op: Operation_SET, Data: []byte("a")}}
+	sign()
+	require.Nil(t, db.CanEndorse(s))
+	require.Nil(t, db.Apply(s))
+
+	_, v, err := db.Get("key")
+	require.Nil(t, err)
+	require.NotNil(t, v.Matches(version.NoVersion))
+
+	s, sign = getTestSpore(db)
+	s.Requirements = map[string]*version.V{"key": version.NoVersion}
+	s.Operations = []*Operation{{Key: "key", Op: Operation_SET, Data: []byte("b")}}
+	sign()
+	require.Exactly(t, version.ErrVersionMismatch, db.CanEndorse(s))
+
+	// Current versions are required as before
+	s.Requirements = map[string]*version.V{"key": v}
+	sign()
+	require.Nil(t, db.CanEndorse(s))
+}
@@ -0,0 +1,95 @@
+package db
+
+import (
+	"errors"
+	"time"
+)
+
+// A spore may depend on other spores, identified by their uuid: it is only
+// endorsed once its dependencies have been applied locally. Until then, it
+// is parked in the waiting list, and re-evaluated when a dependency settles.
+// A spore is dropped if one of its dependencies expires, is vetoed or
+// cancelled, as it could not be applied anymore.
+//
+// Applied spores are only remembered until the end of their grace period,
+// dependencies shall therefore be submitted shortly before their dependents.
+
+// Error messages for dependencies.
+var (
+	ErrPendingDependency = errors.New("unable to endorse a spore before its dependencies are applied")
+	ErrFailedDependency  = errors.New("unable to endorse a spore whose dependency expired or was vetoed")
+)
+
+// dependencyIndex indexes waiting triggers by the uuids of their dependencies.
+type dependencyIndex map[string]map[string]*dbTrigger
+
+func (i dependencyIndex) add(t *dbTrigger) {
+	for _, uuid := range t.spore.Dependencies {
+		triggers, ok := i[uuid]
+		if !ok {
+			triggers = make(map[string]*dbTrigger)
+			i[uuid] = triggers
+		}
+		triggers[t.spore.Uuid] = t
+	}
+}
+
+func (i dependencyIndex) remove(t *dbTrigger) {
+	for _, uuid := range t.spore.Dependencies {
+		triggers, ok := i[uuid]
+		if !ok {
+			continue
+		}
+
+		delete(triggers, t.spore.Uuid)
+		if len(triggers) == 0 {
+			delete(i, uuid)
+		}
+	}
+}
+
+// dependents appends the indexed triggers depending on the spore to the
+// provided ones, skipping the triggers already provided.
+func (i dependencyIndex) dependents(s *Spore, triggers []*dbTrigger) []*dbTrigger {
+	seen := make(map[string]bool)
+	for _, t := range triggers {
+		seen[t.spore.Uuid] = true
+	}
+
+	for uuid, t := range i[s.Uuid] {
+		if !seen[uuid] {
+			triggers = append(triggers, t)
+		}
+	}
+	return triggers
+}
+
+// checkDependencies checks that the dependencies of the spore have been applied.
+// It is thread-safe.
+func (db *DB) checkDependencies(s *Spore) error {
+	for _, uuid := range s.Dependencies {
+		db.appliedMutex.Lock()
+		_, applied := db.applied[uuid]
+		_, vetoed := db.vetoed[uuid]
+		db.appliedMutex.Unlock()
+
+		if vetoed {
+			return ErrFailedDependency
+		}
+
+		if !applied {
+			return ErrPendingDependency
+		}
+	}
+
+	return nil
+}
+
+// expire records the expired spore as vetoed until the next cleaning,
+// so that its dependents are dropped.
+func (db *DB) expire(s *Spore) {
+	deadline := time.Unix(s.Deadline.GetSeconds(), 0)
+	db.appliedMutex.Lock()
+	db.vetoed[s.Uuid] = deadline
+	db.appliedMutex.Unlock()
+}
@@ -85,6 +85,26 @@ func (s *S) SetBatch(keys []string, values [][]byte, versions []*version.V) erro
 	})
 }
 
+// Delete removes the specified key.
+func (s *S) Delete(key string) error {
+	return s.DeleteBatch([]string{key})
+}
+
+// DeleteBatch removes the given keys in a atomic way.
+func (s *S) DeleteBatch(keys []string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(bucketName)
+
+		for _, k := range keys {
+			if err := b.Delete([]byte(k)); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
 // List returns the map of keys with their values.
 func (s *S) List() (map[string]*version.V, error) {
 	catalog := make(map[string]*version.V)
@@ -100,3 +100,21 @@ func TestS_List(t *testing.T) {
 	require.Contains(t, catalog, "testBatch_c")
 	require.Exactly(t, catalog["testList"], v)
 }
+
+func TestS_Delete(t *testing.T) {
+	d := []byte("Content")
+	require.Nil(t, ts.SetBatch(
+		[]string{"testDelete_a", "testDelete_b", "testDelete_c"},
+		[][]byte{d, d, d},
+		[]*version.V{version.New(d), version.New(d), version.New(d)},
+	))
+
+	require.Nil(t, ts.Delete("testDelete_a"))
+	require.Nil(t, ts.DeleteBatch([]string{"testDelete_b", "testDelete_c", "testUnknown"}))
+
+	catalog, err := ts.List()
+	require.Nil(t, err)
+	require.NotContains(t, catalog, "testDelete_a")
+	require.NotContains(t, catalog, "testDelete_b")
+	require.NotContains(t, catalog, "testDelete_c")
+}
@@ -82,6 +82,21 @@ func (s *S) SetBatch(keys []string, values [][]byte, versions []*version.V) erro
 	return s.db.Write(wo, batch)
 }
 
+// Delete removes the specified key.
+func (s *S) Delete(key string) error {
+	return s.db.Delete(wo, []byte(key))
+}
+
+// DeleteBatch removes the given keys in a atomic way.
+func (s *S) DeleteBatch(keys []string) error {
+	batch := gorocksdb.NewWriteBatch()
+	for _, k := range keys {
+		batch.Delete([]byte(k))
+	}
+
+	return s.db.Write(wo, batch)
+}
+
 // List returns the map of keys with their values.
 func (s *S) List() (map[string]*version.V, error) {
 	it := s.db.NewIterator(ro)
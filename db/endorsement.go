@@ -2,6 +2,7 @@ package db
 
 import (
 	"errors"
+	"fmt"
 	"sync"
 	"time"
 
@@ -15,95 +16,180 @@ import (
 // Error messages
 var (
 	ErrDeadlineExpired        = errors.New("unable to endorse a spore with expired deadline")
+	ErrDeadlineMissing        = errors.New("unable to endorse a spore without deadline")
+	ErrDeadlineTooFar         = errors.New("unable to endorse a spore with a deadline beyond the policy timeout")
+	ErrDeadlineBeyondValidity = errors.New("unable to endorse a spore with a deadline beyond its signed validity")
+	ErrNotBeforeTooFar        = errors.New("unable to endorse a spore with a not-before time beyond the policy max delay")
 	ErrConflictingWithStaging = errors.New("unable to endorse a spore due to conflicting promise")
 	ErrBehindRequirement      = errors.New("unable to endorse a spore due to unfulfillable requirement")
 
+	ErrNoEmitter             = errors.New("unable to submit a spore without emitter")
 	ErrNoRelatedSpore        = errors.New("unable to find related spore")
 	ErrDuplicatedEndorsement = errors.New("duplicated endorsement")
 	ErrUnallowedEndorser     = errors.New("unallowed endorser")
 
 	ErrGracePeriodExpired    = errors.New("unable to apply a spore with expired grace period")
 	ErrDuplicatedApplication = errors.New("duplicated application")
+	ErrNotBefore             = errors.New("unable to apply a spore before its not-before time")
 )
 
+// DefaultStallInterval is the default delay after which the endorsements of
+// a spore still staged are requested to peers. See DB.StallInterval.
+const DefaultStallInterval = 5 * time.Second
+
 // CanEndorse checks wether a Spore can be endorsed or not regarding current database status.
 // It is thread-safe.
 func (db *DB) CanEndorse(s *Spore) error {
+	return db.checkEndorsement(s, nil)
+}
+
+// checkEndorsement runs the endorsement checks of the spore, until the first failure.
+// The checks are recorded in the simulation, if not nil.
+func (db *DB) checkEndorsement(s *Spore, r *Simulation) error {
 	// Timeout: Check deadline
-	if !s.checkDeadline() {
-		return ErrDeadlineExpired
+	var err error
+	if s.Deadline == nil {
+		err = ErrDeadlineMissing
+	} else if !s.checkDeadline() {
+		err = ErrDeadlineExpired
+	} else if !s.checkValidity() {
+		err = ErrDeadlineBeyondValidity
+	}
+
+	if err = r.check("deadline", err); err != nil {
+		return err
+	}
+
+	policies := db.getPolicies(s)
+	if policies == nil {
+		err = ErrUnknownPolicy
+	}
+
+	if err = r.check("policies", err); err != nil {
+		return err
+	}
+
+	for _, policy := range policies {
+		if deadlineToDuration(s.Deadline) > policy.MaxTimeout()+MaxClockDrift {
+			err = ErrDeadlineTooFar
+		} else if s.untilNotBefore() > policy.MaxDelay()+MaxClockDrift {
+			err = ErrNotBeforeTooFar
+		}
+
+		if err = r.check("timeout "+policy.Uuid, err); err != nil {
+			return err
+		}
+	}
+
+	// Order: Check that the dependencies have been applied
+	if err = r.check("dependencies", db.checkDependencies(s)); err != nil {
+		return err
 	}
 
 	// Consistency: Check that the operations are no behind the state and fulfill the types
 	db.Store.Lock()
 	for k, v := range s.Requirements {
-		_, v2, err := db.Store.Get(k)
-		if err != nil {
-			db.Store.Unlock()
-			return err
+		_, v2, err := db.Get(k)
+		if err == nil || err == ErrNotFound {
+			err = v2.Matches(v) // missing keys may be required with no version
 		}
 
-		if v2.Matches(v) != nil {
+		if err = r.check("requirement "+k, err); err != nil {
 			db.Store.Unlock()
 			return err
 		}
 	}
 
 	values := make(map[string]*operations.Value)
-	var oldSize uint64
+	before := make(map[string][]byte)
 
-	for _, op := range s.Operations {
+	for i, op := range s.Operations {
 		v, ok := values[op.Key]
 		if !ok {
 			d, _, _ := db.Store.Get(op.Key)
-			oldSize += uint64(len(d))
+			before[op.Key] = d
 			values[op.Key] = operations.NewValue(d)
 			v = values[op.Key]
 		}
 
-		err := op.Exec(v)
-		if err != nil {
-			db.Store.Unlock()
-			return err
+		policy := s.opPolicy(op)
+		err = op.Exec(v)
+		if err == nil && !s.involves(policy) {
+			err = ErrOpPolicyNotInvolved
+		}
+		if err == nil {
+			err = db.Check(policy, op, v)
 		}
 
-		err = db.Check(s.Policy, op, v)
-		if err != nil {
+		if err = r.check(fmt.Sprintf("operation #%d %s %s (%s)", i+1, op.Op, op.Key, policy), err); err != nil {
 			db.Store.Unlock()
 			return err
 		}
 	}
 
 	db.Store.Unlock()
+	r.setValues(values)
 
-	err := db.checkCurrentPolicyUsage(oldSize, s.Policy, values)
-	if err != nil {
+	// Authorization: Check that the required parties signed the spore
+	if err = r.check("cosignatures", db.checkCosignatures(s)); err != nil {
 		return err
 	}
 
+	split, err := s.splitValues(before, values)
+	if err = r.check("key policies", err); err != nil {
+		return err
+	}
+
+	// Invariants and usages are checked per policy, on the keys it governs
+	for _, policy := range policies {
+		pv := split[policy.Uuid]
+		r.addUsage(db, s, policy, pv)
+
+		err = db.CheckInvariants(policy.Uuid, pv.before, pv.after)
+		if err = r.check("invariants "+policy.Uuid, err); err != nil {
+			return err
+		}
+
+		err = db.checkCurrentPolicyUsage(pv.oldSize, policy.Uuid, pv.after)
+		if err = r.check("policy quota "+policy.Uuid, err); err != nil {
+			return err
+		}
+
+		err = db.checkEmitterQuota(s, policy, pv.oldSize, pv.after)
+		if err = r.check("emitter quota "+policy.Uuid, err); err != nil {
+			return err
+		}
+	}
+
 	// Promise: Check for conflicts with staging
-	return db.checkConflictWithStaging(s)
+	err = nil
+	if conflict := db.conflictingStaged(s); conflict != "" {
+		r.setConflict(conflict)
+		err = ErrConflictingWithStaging
+	}
+
+	return r.check("conflicts", err)
 }
 
-func (db *DB) checkConflictWithStaging(s *Spore) error {
+// conflictingStaged returns the uuid of a staged spore conflicting with the spore, if any.
+func (db *DB) conflictingStaged(s *Spore) string {
 	db.stagingMutex.RLock()
 	defer db.stagingMutex.RUnlock()
 
-	for _, s2 := range db.staging {
+	for _, s2 := range db.stagingIndex.related(s) {
 		if s.CheckConflict(s2.spore) != nil {
-			return ErrConflictingWithStaging
+			return s2.spore.Uuid
 		}
 	}
 
-	return nil
+	return ""
 }
 
 // Submit broadcasts the Spore to the Mycelium, then tries to endorse it with current state.
 func (db *DB) Submit(s *Spore) (err error) {
 	// Sign the spore before submission
-	s.Emitter, s.Signature = db.Identity, nil // ensure nil signature before hash
-	s.Signature, err = db.KeyRing.Sign(hashMessage(s))
-	if err != nil {
+	s.Emitter = db.Identity
+	if err = s.Sign(db.KeyRing); err != nil {
 		zap.L().Error("Unable to sign the spore",
 			zap.String("uuid", s.Uuid),
 			zap.String("step", "submission"),
@@ -116,18 +202,31 @@ func (db *DB) Submit(s *Spore) (err error) {
 	return db.Endorse(s)
 }
 
+// SubmitSigned broadcasts a Spore already signed by its emitter, such as a client
+// known by the keyring, then tries to endorse it with current state.
+func (db *DB) SubmitSigned(s *Spore) error {
+	if s.Emitter == "" {
+		return ErrNoEmitter
+	}
+
+	if err := db.VerifySporeSignature(*s); err != nil {
+		return err
+	}
+
+	db.Messages <- s
+	return db.Endorse(s)
+}
+
 // VerifySporeSignature verifies emitter's signature of the given spore.
 // It is passed by value because this function require's spore alteration.
 func (db *DB) VerifySporeSignature(s Spore) error {
-	signature := s.Signature
-	s.Signature = nil
-	hash := hashMessage(&s)
+	hash := s.signingHash()
 
 	if s.Emitter == db.Identity {
 		s.Emitter = ""
 	}
 
-	return db.KeyRing.Verify(s.Emitter, hash, signature)
+	return db.KeyRing.Verify(s.Emitter, hash, s.Signature)
 }
 
 // Endorse tries to endorse a Spore, calling CanEndorse before any operation.
@@ -138,34 +237,55 @@ func (db *DB) Endorse(s *Spore) error {
 		return err
 	}
 
+	switch db.Status(s.Uuid) {
+	case SporeStatus_DROPPED:
+		return ErrVetoed
+	case SporeStatus_APPLIED:
+		return ErrDuplicatedApplication
+	case SporeStatus_UNKNOWN:
+		if db.cancelled(s) {
+			return ErrVetoed
+		}
+	}
+
 	err = db.CanEndorse(s)
-	if err == ErrConflictingWithStaging {
+	if err == ErrConflictingWithStaging || err == ErrPendingDependency {
 		db.waitingMutex.Lock()
-		db.waiting[s.Uuid] = &dbTrigger{
+		db.addWaiting(&dbTrigger{
 			spore: s,
-		}
+		})
 		db.waitingMutex.Unlock()
+		db.adoptOrphans(s.Uuid)
 		return nil
 	} else if err == nil {
-		db.executeEndorsement(s)
+		db.executeEndorsement(s, nil)
+		db.adoptOrphans(s.Uuid)
 		return nil
 	}
 	return err
 }
 
-func (db *DB) executeEndorsement(s *Spore) {
-	policy := db.policies[s.Policy]
-	if policy.Quorum == 0 {
-		_ = db.Apply(s)
+// executeEndorsement endorses the spore, and stages it with the provided
+// endorsements, previously received while the spore was waiting.
+func (db *DB) executeEndorsement(s *Spore, endorsements []*Endorsement) {
+	policies := db.getPolicies(s)
+	if policies == nil {
 		return
 	}
 
-	pub, _, _ := db.KeyRing.GetPublic("")
-	endorser := policy.pubToEndorser(pub)
-	var endorsements []*Endorsement
+	if db.quorumReached(policies, nil) { // no endorsement required
+		if s.untilNotBefore() > 0 {
+			db.stage(s, policies, nil) // committed until its not-before time
+		} else if db.apply(s, policies) == nil {
+			go db.release(s)
+		}
+		return
+	}
 
-	if endorser != nil {
-		signature, err := db.KeyRing.Sign(db.HashSpore(s))
+	var e *Endorsement
+	if db.isEndorserOfAny(policies, db.Identity) {
+		var err error
+		e, err = db.endorsement(s)
 		if err != nil {
 			zap.L().Error("Unable to sign the spore",
 				zap.String("uuid", s.Uuid),
@@ -175,36 +295,147 @@ func (db *DB) executeEndorsement(s *Spore) {
 			return
 		}
 
-		e := &Endorsement{
-			Uuid:      s.Uuid,
-			Emitter:   db.Identity,
-			Signature: signature,
-		}
-
-		db.Messages <- e // Broadcast our endorsement for this spore
+		endorsements = append(endorsements, e)
+	}
 
-		// If the policy only requires one endorsement, bypass staging list
-		if db.policies[s.Policy].Quorum == 1 {
-			_ = db.Apply(s)
-			return
+	// If the quorum is already reached, bypass staging list unless the spore is not due yet
+	if db.quorumReached(policies, endorsements) && s.untilNotBefore() <= 0 {
+		if db.apply(s, policies) == nil {
+			db.keepEndorsements(s.Uuid, endorsements)
 		}
+		go db.release(s)
+	} else {
+		db.stage(s, policies, endorsements)
+	}
 
-		endorsements = []*Endorsement{e}
+	// Broadcast our endorsement for this spore, once our promise is stored
+	if e != nil {
+		db.Messages <- e
 	}
+}
 
+// endorsement returns our signed endorsement for the spore.
+func (db *DB) endorsement(s *Spore) (*Endorsement, error) {
+	signature, err := db.KeyRing.Sign(db.HashSpore(s))
+	if err != nil {
+		return nil, err
+	}
+
+	return &Endorsement{
+		Uuid:      s.Uuid,
+		Emitter:   db.Identity,
+		Signature: signature,
+	}, nil
+}
+
+// stage adds the spore to the staging list, until its deadline.
+func (db *DB) stage(s *Spore, policies []*Policy, endorsements []*Endorsement) {
 	db.stagingMutex.Lock()
 	defer db.stagingMutex.Unlock()
 
 	timer := time.AfterFunc(
 		deadlineToDuration(s.Deadline),
-		func() { db.gc <- s },
+		func() { db.release(s) },
 	)
 
-	db.staging[s.Uuid] = &dbTrigger{
+	trigger := &dbTrigger{
 		timer:        timer,
 		spore:        s,
 		endorsements: endorsements,
+		policies:     policies,
+	}
+	if db.StallInterval > 0 {
+		trigger.stall = time.AfterFunc(db.StallInterval, func() { db.requestEndorsements(s.Uuid) })
+	}
+	db.addStaging(trigger)
+
+	if db.quorumReached(policies, endorsements) {
+		db.commit(trigger)
+	}
+}
+
+// commit applies the staged spore which reached its quorum, once its not-before
+// time is over. Until then, the spore stays staged without deadline, so that
+// conflicting spores keep waiting.
+// It must be called with stagingMutex locked.
+func (db *DB) commit(t *dbTrigger) {
+	if t.committed {
+		return
 	}
+
+	t.committed = true
+	t.timer.Stop()
+	t.timer = time.AfterFunc(t.spore.untilNotBefore(), func() {
+		db.stagingMutex.Lock()
+		removed := db.removeStaging(t.spore.Uuid) != nil
+		endorsements := t.endorsements
+		db.stagingMutex.Unlock()
+
+		if removed {
+			if db.apply(t.spore, t.policies) == nil {
+				db.keepEndorsements(t.spore.Uuid, endorsements)
+			}
+			db.release(t.spore)
+		}
+	})
+}
+
+// requestEndorsements asks peers for the endorsements of the spore, if still staged.
+// The request is repeated until the spore settles.
+func (db *DB) requestEndorsements(uuid string) {
+	db.stagingMutex.Lock()
+	t, staged := db.staging[uuid]
+	stalled := staged && !t.committed
+	if stalled {
+		t.stall.Reset(db.StallInterval)
+	}
+	db.stagingMutex.Unlock()
+
+	if !stalled {
+		return
+	}
+
+	zap.L().Info("Requesting endorsements",
+		zap.String("uuid", uuid),
+	)
+
+	db.Messages <- &EndorsementRequest{Uuid: uuid}
+}
+
+// Endorsements returns the endorsements registered for the staged, waiting
+// or recently applied spore.
+// It is thread-safe.
+func (db *DB) Endorsements(uuid string) []*Endorsement {
+	db.waitingMutex.RLock()
+	db.stagingMutex.RLock()
+	t, ok := db.staging[uuid]
+	if !ok {
+		t, ok = db.waiting[uuid]
+	}
+
+	var endorsements []*Endorsement
+	if ok {
+		endorsements = make([]*Endorsement, len(t.endorsements))
+		copy(endorsements, t.endorsements)
+	}
+	db.stagingMutex.RUnlock()
+	db.waitingMutex.RUnlock()
+
+	if ok {
+		return endorsements
+	}
+
+	db.appliedMutex.Lock()
+	defer db.appliedMutex.Unlock()
+	return db.endorsed[uuid] // never modified once applied
+}
+
+// keepEndorsements keeps the endorsements of the applied spore,
+// for peers which missed them.
+func (db *DB) keepEndorsements(uuid string, endorsements []*Endorsement) {
+	db.appliedMutex.Lock()
+	defer db.appliedMutex.Unlock()
+	db.endorsed[uuid] = endorsements
 }
 
 // AddEndorsement registers the incoming endorsement.
@@ -216,21 +447,26 @@ func (db *DB) AddEndorsement(e *Endorsement) error {
 	}
 
 	if trigger == nil {
-		_, werr := db.addEndorsementMap(e, db.waiting, &db.waitingMutex)
+		waiting, werr := db.addEndorsementMap(e, db.waiting, &db.waitingMutex)
+		if werr == ErrNoRelatedSpore {
+			db.addOrphan(e) // the spore may arrive later
+		} else if werr == nil {
+			db.detectEquivocation(waiting, e)
+			db.resolveConflicts(waiting.spore) // our staged spores may be dead now
+		}
 		return werr
 	}
 
+	db.detectEquivocation(trigger, e)
+
 	// Should we execute the spore?
 	db.stagingMutex.Lock()
-	defer db.stagingMutex.Unlock()
-
-	policy := db.policies[trigger.spore.Policy]
-	if policy.Quorum <= uint64(len(trigger.endorsements)) {
-		trigger.timer.Stop()
-		delete(db.staging, trigger.spore.Uuid)
-		go func() { _ = db.Apply(trigger.spore) }()
+	if db.staging[trigger.spore.Uuid] == trigger && db.quorumReached(trigger.policies, trigger.endorsements) {
+		db.commit(trigger)
 	}
+	db.stagingMutex.Unlock()
 
+	db.resolveConflicts(trigger.spore)
 	return nil
 }
 
@@ -251,6 +487,10 @@ func (db *DB) addEndorsementMap(e *Endorsement, ma map[string]*dbTrigger, mu syn
 		}
 	}
 
+	if db.isExcluded(e.Emitter) {
+		return nil, ErrExcludedEndorser
+	}
+
 	// Known endorser?
 	emitter := e.Emitter
 	if e.Emitter == db.Identity {
@@ -269,7 +509,7 @@ func (db *DB) addEndorsementMap(e *Endorsement, ma map[string]*dbTrigger, mu syn
 	}
 
 	// Allowed endorser?
-	if db.policies[trigger.spore.Policy].pubToEndorser(pub) == nil {
+	if !pubToAnyEndorser(db.triggerPolicies(trigger), pub) {
 		zap.L().Warn("Invalid endorsement",
 			zap.String("uuid", e.Uuid),
 			zap.String("endorser", e.Emitter),
@@ -50,20 +50,103 @@ func (m *Endorsement) GetSignature() []byte {
 	return nil
 }
 
+// Veto withdraws the endorsement of a spore which cannot reach its quorum
+// anymore, due to conflicting spores of higher priority.
+type Veto struct {
+	Emitter   string `protobuf:"bytes,1,opt,name=emitter" json:"emitter,omitempty"`
+	Uuid      string `protobuf:"bytes,2,opt,name=uuid" json:"uuid,omitempty"`
+	Signature []byte `protobuf:"bytes,3,opt,name=signature,proto3" json:"signature,omitempty"`
+}
+
+func (m *Veto) Reset()                    { *m = Veto{} }
+func (m *Veto) String() string            { return proto.CompactTextString(m) }
+func (*Veto) ProtoMessage()               {}
+func (*Veto) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{1} }
+
+func (m *Veto) GetEmitter() string {
+	if m != nil {
+		return m.Emitter
+	}
+	return ""
+}
+
+func (m *Veto) GetUuid() string {
+	if m != nil {
+		return m.Uuid
+	}
+	return ""
+}
+
+func (m *Veto) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+// EndorsementRequest asks peers for the endorsements they hold for a spore,
+// which is stalled in the staging list of the emitter.
+type EndorsementRequest struct {
+	Uuid string `protobuf:"bytes,1,opt,name=uuid" json:"uuid,omitempty"`
+}
+
+func (m *EndorsementRequest) Reset()                    { *m = EndorsementRequest{} }
+func (m *EndorsementRequest) String() string            { return proto.CompactTextString(m) }
+func (*EndorsementRequest) ProtoMessage()               {}
+func (*EndorsementRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
+
+func (m *EndorsementRequest) GetUuid() string {
+	if m != nil {
+		return m.Uuid
+	}
+	return ""
+}
+
+// Cancel withdraws a spore before its application. It is signed by the
+// emitter of the spore.
+type Cancel struct {
+	Uuid      string `protobuf:"bytes,1,opt,name=uuid" json:"uuid,omitempty"`
+	Signature []byte `protobuf:"bytes,2,opt,name=signature,proto3" json:"signature,omitempty"`
+}
+
+func (m *Cancel) Reset()                    { *m = Cancel{} }
+func (m *Cancel) String() string            { return proto.CompactTextString(m) }
+func (*Cancel) ProtoMessage()               {}
+func (*Cancel) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
+
+func (m *Cancel) GetUuid() string {
+	if m != nil {
+		return m.Uuid
+	}
+	return ""
+}
+
+func (m *Cancel) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*Endorsement)(nil), "db.Endorsement")
+	proto.RegisterType((*Veto)(nil), "db.Veto")
+	proto.RegisterType((*EndorsementRequest)(nil), "db.EndorsementRequest")
+	proto.RegisterType((*Cancel)(nil), "db.Cancel")
 }
 
 func init() { proto.RegisterFile("db/endorsement.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 117 bytes of a gzipped FileDescriptorProto
+	// 160 bytes of a gzipped FileDescriptorProto
 	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0x12, 0x49, 0x49, 0xd2, 0x4f,
 	0xcd, 0x4b, 0xc9, 0x2f, 0x2a, 0x4e, 0xcd, 0x4d, 0xcd, 0x2b, 0xd1, 0x2b, 0x28, 0xca, 0x2f, 0xc9,
 	0x17, 0x62, 0x4a, 0x49, 0x52, 0x8a, 0xe4, 0xe2, 0x76, 0x45, 0x48, 0x08, 0x49, 0x70, 0xb1, 0xa7,
 	0xe6, 0x66, 0x96, 0x94, 0xa4, 0x16, 0x49, 0x30, 0x2a, 0x30, 0x6a, 0x70, 0x06, 0xc1, 0xb8, 0x42,
 	0x42, 0x5c, 0x2c, 0xa5, 0xa5, 0x99, 0x29, 0x12, 0x4c, 0x60, 0x61, 0x30, 0x5b, 0x48, 0x86, 0x8b,
 	0xb3, 0x38, 0x33, 0x3d, 0x2f, 0xb1, 0xa4, 0xb4, 0x28, 0x55, 0x82, 0x59, 0x81, 0x51, 0x83, 0x27,
-	0x08, 0x21, 0x90, 0xc4, 0x06, 0xb6, 0xc5, 0x18, 0x10, 0x00, 0x00, 0xff, 0xff, 0x19, 0x64, 0x32,
-	0x49, 0x7d, 0x00, 0x00, 0x00,
+	0x08, 0x21, 0xa0, 0x14, 0xc4, 0xc5, 0x12, 0x96, 0x5a, 0x92, 0x4f, 0x55, 0x33, 0x35, 0xb8, 0x84,
+	0x90, 0x9c, 0x1b, 0x94, 0x5a, 0x58, 0x9a, 0x5a, 0x5c, 0x02, 0x37, 0x87, 0x11, 0x61, 0x8e, 0x92,
+	0x15, 0x17, 0x9b, 0x73, 0x62, 0x5e, 0x72, 0x6a, 0x0e, 0x36, 0x59, 0x54, 0x5b, 0x98, 0xd0, 0x6c,
+	0x49, 0x62, 0x03, 0x87, 0x8f, 0x31, 0x60, 0x00, 0x88, 0xa9, 0x6e, 0x07, 0x37, 0x01, 0x00, 0x00,
 }
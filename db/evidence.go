@@ -0,0 +1,187 @@
+package db
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"go.uber.org/zap"
+)
+
+// Endorsers promise not to endorse spores conflicting with the ones they
+// endorsed, until their deadline or their veto. An endorser whose endorsements
+// of two conflicting spores are received while both spores are pending breaks
+// this promise: once it had the opportunity to veto one of them, both signed
+// endorsements are kept as evidence and broadcasted.
+//
+// Evidences are only informative, as the absence of veto cannot be proven to
+// third parties. Operators may lower the trust of the faulty key, or exclude
+// it from quorums with Exclude.
+
+const evidenceKeyPrefix = LocalKeyPrefix + "evidence/"
+
+// EquivocationDelay is the delay given to an endorser to veto one of two
+// conflicting spores it endorsed, before its endorsements are kept as evidence.
+var EquivocationDelay = 2 * time.Second
+
+// Error messages for equivocation evidences.
+var (
+	ErrInvalidEvidence    = errors.New("invalid equivocation evidence")
+	ErrDuplicatedEvidence = errors.New("equivocation evidence already known")
+	ErrExcludedEndorser   = errors.New("the endorser is excluded from quorums")
+)
+
+func evidenceKey(e *Evidence) string {
+	uuids := []string{e.First.GetUuid(), e.Second.GetUuid()}
+	sort.Strings(uuids)
+	return evidenceKeyPrefix + e.Emitter + "/" + uuids[0] + "/" + uuids[1]
+}
+
+// Exclude replaces the set of identities whose endorsements are ignored.
+// It is thread-safe.
+func (db *DB) Exclude(identities ...string) {
+	excluded := make(map[string]bool)
+	for _, identity := range identities {
+		excluded[identity] = true
+	}
+
+	db.excludedMutex.Lock()
+	db.excluded = excluded
+	db.excludedMutex.Unlock()
+}
+
+func (db *DB) isExcluded(identity string) bool {
+	db.excludedMutex.RLock()
+	defer db.excludedMutex.RUnlock()
+	return db.excluded[identity]
+}
+
+// detectEquivocation looks for pending spores conflicting with the endorsed one,
+// and endorsed by the same emitter.
+// It is thread-safe.
+func (db *DB) detectEquivocation(trigger *dbTrigger, e *Endorsement) {
+	db.waitingMutex.RLock()
+	db.stagingMutex.RLock()
+	defer db.stagingMutex.RUnlock()
+	defer db.waitingMutex.RUnlock()
+
+	for _, index := range []sporeIndex{db.stagingIndex, db.waitingIndex} {
+		for _, other := range index.related(trigger.spore) {
+			if other.spore.CheckConflict(trigger.spore) == nil {
+				continue
+			}
+
+			for _, e2 := range other.endorsements {
+				if e2.Emitter == e.Emitter {
+					evidence := &Evidence{
+						Emitter:         e.Emitter,
+						First:           other.spore,
+						FirstSignature:  e2.Signature,
+						Second:          trigger.spore,
+						SecondSignature: e.Signature,
+					}
+
+					time.AfterFunc(EquivocationDelay, func() {
+						db.confirmEquivocation(evidence, other, trigger)
+					})
+				}
+			}
+		}
+	}
+}
+
+// confirmEquivocation keeps the evidence if none of the spores has been vetoed
+// by its emitter.
+func (db *DB) confirmEquivocation(evidence *Evidence, a, b *dbTrigger) {
+	var vetoed bool
+
+	db.waitingMutex.RLock()
+	db.stagingMutex.RLock()
+	for _, t := range []*dbTrigger{a, b} {
+		for _, emitter := range t.vetoes {
+			vetoed = vetoed || emitter == evidence.Emitter
+		}
+	}
+	db.stagingMutex.RUnlock()
+	db.waitingMutex.RUnlock()
+
+	if !vetoed && db.AddEvidence(evidence) == nil {
+		db.Messages <- evidence
+	}
+}
+
+// AddEvidence verifies and stores an equivocation evidence.
+// It returns ErrDuplicatedEvidence if the evidence is already stored.
+func (db *DB) AddEvidence(e *Evidence) error {
+	if err := db.verifyEvidence(e); err != nil {
+		return err
+	}
+
+	key := evidenceKey(e)
+	if _, _, err := db.Store.Get(key); err == nil {
+		return ErrDuplicatedEvidence
+	}
+
+	raw, err := proto.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	zap.L().Warn("Equivocation",
+		zap.String("endorser", e.Emitter),
+		zap.String("first", e.First.Uuid),
+		zap.String("second", e.Second.Uuid),
+	)
+	return db.Store.Set(key, raw, nil)
+}
+
+func (db *DB) verifyEvidence(e *Evidence) error {
+	if e.First == nil || e.Second == nil || e.First.Uuid == e.Second.Uuid ||
+		strings.Contains(e.Emitter, "/") || e.First.CheckConflict(e.Second) == nil {
+		return ErrInvalidEvidence
+	}
+
+	emitter := e.Emitter
+	if emitter == db.Identity {
+		emitter = ""
+	}
+
+	// Endorsements are signed on the canonical hash, see HashSpore
+	if err := db.KeyRing.Verify(emitter, e.First.canonicalHash(), e.FirstSignature); err != nil {
+		return err
+	}
+	return db.KeyRing.Verify(emitter, e.Second.canonicalHash(), e.SecondSignature)
+}
+
+// Evidences returns every stored equivocation evidence.
+func (db *DB) Evidences() ([]*Evidence, error) {
+	catalog, err := db.Store.List()
+	if err != nil {
+		return nil, err
+	}
+
+	var evidences []*Evidence
+	for key := range catalog {
+		if !strings.HasPrefix(key, evidenceKeyPrefix) {
+			continue
+		}
+
+		raw, _, err := db.Store.Get(key)
+		if err != nil {
+			return nil, err
+		}
+
+		e := &Evidence{}
+		if err = proto.Unmarshal(raw, e); err != nil {
+			return nil, err
+		}
+		evidences = append(evidences, e)
+	}
+
+	sort.Slice(evidences, func(i, j int) bool {
+		return evidenceKey(evidences[i]) < evidenceKey(evidences[j])
+	})
+	return evidences, nil
+}
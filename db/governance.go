@@ -0,0 +1,164 @@
+package db
+
+import (
+	"errors"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/golang/protobuf/proto"
+
+	"gitlab.com/SporeDB/sporedb/db/operations"
+)
+
+// MetaPolicy is the uuid of the policy governing every other policy.
+//
+// Policies are stored on-ledger under internal keys, and can only be modified
+// by spores endorsed under the meta-policy. This way, every node switches
+// endorsers, quorum and specifications at the same logical point.
+const MetaPolicy = "meta"
+
+const policyKeyPrefix = InternalKeyPrefix + "/policy/"
+
+// Error messages for policy governance.
+var (
+	ErrInvalidPolicyUpdate = errors.New("the requested policy update is invalid")
+)
+
+// PolicyKey returns the internal key storing the provided policy on-ledger.
+func PolicyKey(uuid string) string {
+	return policyKeyPrefix + uuid
+}
+
+// NewPolicyOperation returns the operation replacing the on-ledger policy.
+// It must be submitted in a spore under the meta-policy.
+func NewPolicyOperation(p *Policy) (*Operation, error) {
+	if _, err := p.compile(); err != nil {
+		return nil, err
+	}
+
+	data, err := proto.Marshal(p)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Operation{
+		Key:  PolicyKey(p.Uuid),
+		Op:   Operation_SET,
+		Data: data,
+	}, nil
+}
+
+// NewPolicyRemoval returns the operation removing the on-ledger policy.
+// It must be submitted in a spore under the meta-policy.
+func NewPolicyRemoval(uuid string) *Operation {
+	return &Operation{
+		Key: PolicyKey(uuid),
+		Op:  Operation_SET,
+	}
+}
+
+// checkPolicyUpdate validates a simulated policy update.
+// An empty value stands for the removal of the policy.
+func (db *DB) checkPolicyUpdate(o *Operation, value *operations.Value) error {
+	if o.Op != Operation_SET {
+		return ErrOpNotAllowed
+	}
+
+	uuid := strings.TrimPrefix(o.Key, policyKeyPrefix)
+	if len(value.Raw) == 0 {
+		if uuid == MetaPolicy {
+			return ErrInvalidPolicyUpdate // the meta-policy cannot be removed
+		}
+		return nil
+	}
+
+	p := &Policy{}
+	if proto.Unmarshal(value.Raw, p) != nil || p.Uuid != uuid {
+		return ErrInvalidPolicyUpdate
+	}
+
+	if _, err := p.compile(); err != nil {
+		return ErrInvalidPolicyUpdate
+	}
+
+	db.policiesMutex.RLock()
+	defer db.policiesMutex.RUnlock()
+	return checkClaims(db.policies, p)
+}
+
+// reloadPolicies updates registered policies from freshly applied values.
+// Keys that are not policy keys are ignored.
+func (db *DB) reloadPolicies(keys []string, values [][]byte) {
+	for i, key := range keys {
+		if !strings.HasPrefix(key, policyKeyPrefix) {
+			continue
+		}
+
+		uuid := strings.TrimPrefix(key, policyKeyPrefix)
+		if err := db.loadPolicy(uuid, values[i]); err != nil {
+			zap.L().Error("Unable to load on-ledger policy",
+				zap.String("uuid", uuid),
+				zap.Error(err),
+			)
+			continue
+		}
+
+		zap.L().Info("Policy updated",
+			zap.String("uuid", uuid),
+			zap.Bool("removed", len(values[i]) == 0),
+		)
+	}
+}
+
+func (db *DB) loadPolicy(uuid string, data []byte) error {
+	if len(data) == 0 {
+		db.RemovePolicy(uuid)
+		return nil
+	}
+
+	p := &Policy{}
+	if err := proto.Unmarshal(data, p); err != nil {
+		return err
+	}
+
+	return db.AddPolicy(p)
+}
+
+// isStoredPolicy returns true if the policy is stored on-ledger.
+func (db *DB) isStoredPolicy(uuid string) bool {
+	data, _, err := db.Store.Get(PolicyKey(uuid))
+	return err == nil && len(data) > 0
+}
+
+// LoadStoredPolicies registers every policy stored on-ledger.
+// On-ledger policies take precedence over policies added by AddPolicy,
+// which shall only be used to bootstrap a new network.
+func (db *DB) LoadStoredPolicies() error {
+	catalog, err := db.Store.List()
+	if err != nil {
+		return err
+	}
+
+	for key := range catalog {
+		if !strings.HasPrefix(key, policyKeyPrefix) {
+			continue
+		}
+
+		data, _, err := db.Store.Get(key)
+		if err != nil {
+			return err
+		}
+
+		uuid := strings.TrimPrefix(key, policyKeyPrefix)
+		if err = db.loadPolicy(uuid, data); err != nil {
+			return err
+		}
+
+		zap.L().Info("Loaded on-ledger policy",
+			zap.String("uuid", uuid),
+		)
+	}
+
+	return nil
+}
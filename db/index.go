@@ -0,0 +1,89 @@
+package db
+
+// sporeIndex indexes triggers by the keys of their spores' operations,
+// so that conflicts are only checked between spores sharing keys.
+type sporeIndex map[string]map[string]*dbTrigger
+
+func (i sporeIndex) add(t *dbTrigger) {
+	for _, o := range t.spore.Operations {
+		triggers, ok := i[o.Key]
+		if !ok {
+			triggers = make(map[string]*dbTrigger)
+			i[o.Key] = triggers
+		}
+		triggers[t.spore.Uuid] = t
+	}
+}
+
+func (i sporeIndex) remove(t *dbTrigger) {
+	for _, o := range t.spore.Operations {
+		triggers, ok := i[o.Key]
+		if !ok {
+			continue
+		}
+
+		delete(triggers, t.spore.Uuid)
+		if len(triggers) == 0 {
+			delete(i, o.Key)
+		}
+	}
+}
+
+// related returns the indexed triggers sharing at least one key with the spore.
+// The spore itself is never returned.
+func (i sporeIndex) related(s *Spore) (triggers []*dbTrigger) {
+	seen := map[string]bool{s.Uuid: true}
+	for _, o := range s.Operations {
+		for uuid, t := range i[o.Key] {
+			if !seen[uuid] {
+				seen[uuid] = true
+				triggers = append(triggers, t)
+			}
+		}
+	}
+	return
+}
+
+// addStaging adds the trigger to the staging list, and persists it.
+// It must be called with stagingMutex locked.
+func (db *DB) addStaging(t *dbTrigger) {
+	db.staging[t.spore.Uuid] = t
+	db.stagingIndex.add(t)
+	db.storeStaged(t.spore)
+}
+
+// removeStaging removes a spore from the staging list, and returns its trigger if found.
+// It must be called with stagingMutex locked.
+func (db *DB) removeStaging(uuid string) *dbTrigger {
+	t, ok := db.staging[uuid]
+	if !ok {
+		return nil
+	}
+
+	if t.stall != nil {
+		t.stall.Stop()
+	}
+
+	delete(db.staging, uuid)
+	db.stagingIndex.remove(t)
+	db.forget(stagedKeyPrefix + uuid)
+	return t
+}
+
+// addWaiting adds the trigger to the waiting list.
+// It must be called with waitingMutex locked.
+func (db *DB) addWaiting(t *dbTrigger) {
+	db.waiting[t.spore.Uuid] = t
+	db.waitingIndex.add(t)
+	db.dependencyIndex.add(t)
+}
+
+// removeWaiting removes a spore from the waiting list.
+// It must be called with waitingMutex locked.
+func (db *DB) removeWaiting(uuid string) {
+	if t, ok := db.waiting[uuid]; ok {
+		delete(db.waiting, uuid)
+		db.waitingIndex.remove(t)
+		db.dependencyIndex.remove(t)
+	}
+}
@@ -0,0 +1,259 @@
+package db
+
+import (
+	"errors"
+	"math/big"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"gitlab.com/SporeDB/sporedb/db/encoding"
+	"gitlab.com/SporeDB/sporedb/db/operations"
+)
+
+// Invariant expressions are conjunctions of comparisons between operands:
+//
+//	each(balance:*) >= 0
+//	delta(balance:*) == 0 && count(balance:*) <= 2
+//
+// Operands are either numbers or aggregates computed over the keys touched
+// by the spore and matching a glob pattern (where '*' matches any sequence):
+//
+//	each(pattern)  the comparison must hold for every matching key
+//	sum(pattern)   sum of the values after the spore
+//	delta(pattern) sum of the value changes made by the spore
+//	count(pattern) number of matching keys
+//
+// Values of matching keys must be numeric, empty values standing for zero.
+// Keys that are not touched by the spore are never read.
+
+// Error messages for invariants.
+var (
+	ErrInvalidInvariant = errors.New("the policy contains an invalid invariant")
+)
+
+// ErrInvariantViolated is returned when a spore breaks a policy invariant.
+type ErrInvariantViolated struct {
+	Name string
+}
+
+// Error returns error's string value.
+func (e ErrInvariantViolated) Error() string {
+	return "the requested operations violate the invariant: " + e.Name
+}
+
+type invariant struct {
+	name    string
+	clauses []*invariantClause
+}
+
+type invariantClause struct {
+	left, right *invariantOperand
+	cmp         string
+}
+
+type invariantOperand struct {
+	fn      string // empty for numbers
+	pattern *regexp.Regexp
+	number  *big.Float
+}
+
+var invariantComparators = []string{"==", "!=", "<=", ">=", "<", ">"}
+
+func (p *Policy) compileInvariants() (invariants []*invariant, err error) {
+	for _, i := range p.Invariants {
+		var inv *invariant
+		inv, err = compileInvariant(i)
+		if err != nil {
+			return
+		}
+		invariants = append(invariants, inv)
+	}
+	return
+}
+
+func compileInvariant(i *Invariant) (*invariant, error) {
+	if i.Name == "" {
+		return nil, ErrInvalidInvariant
+	}
+
+	inv := &invariant{name: i.Name}
+	for _, raw := range strings.Split(i.Expression, "&&") {
+		c, err := compileInvariantClause(raw)
+		if err != nil {
+			return nil, err
+		}
+		inv.clauses = append(inv.clauses, c)
+	}
+
+	return inv, nil
+}
+
+func compileInvariantClause(raw string) (c *invariantClause, err error) {
+	c = &invariantClause{}
+	for _, cmp := range invariantComparators {
+		if parts := strings.SplitN(raw, cmp, 2); len(parts) == 2 {
+			c.cmp = cmp
+			if c.left, err = compileInvariantOperand(parts[0]); err != nil {
+				return
+			}
+			if c.right, err = compileInvariantOperand(parts[1]); err != nil {
+				return
+			}
+			break
+		}
+	}
+
+	if c.cmp == "" || (c.left.fn == "each" && c.right.fn == "each") {
+		return nil, ErrInvalidInvariant
+	}
+	return
+}
+
+func compileInvariantOperand(raw string) (*invariantOperand, error) {
+	raw = strings.TrimSpace(raw)
+
+	open := strings.IndexRune(raw, '(')
+	if open < 0 {
+		f, ok := new(big.Float).SetString(raw)
+		if !ok {
+			return nil, ErrInvalidInvariant
+		}
+		return &invariantOperand{number: f}, nil
+	}
+
+	fn := raw[:open]
+	switch fn {
+	case "each", "sum", "delta", "count":
+	default:
+		return nil, ErrInvalidInvariant
+	}
+
+	if !strings.HasSuffix(raw, ")") {
+		return nil, ErrInvalidInvariant
+	}
+
+	glob := strings.TrimSpace(raw[open+1 : len(raw)-1])
+	if glob == "" || strings.IndexFunc(glob, unicode.IsSpace) >= 0 {
+		return nil, ErrInvalidInvariant
+	}
+
+	expr := "^" + strings.Replace(regexp.QuoteMeta(glob), `\*`, ".*", -1) + "$"
+	return &invariantOperand{fn: fn, pattern: regexp.MustCompile(expr)}, nil
+}
+
+// check evaluates the invariant against the values of the keys touched by a spore.
+func (inv *invariant) check(before map[string][]byte, after map[string]*operations.Value) error {
+	keys := make([]string, 0, len(after))
+	for k := range after {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, c := range inv.clauses {
+		ok, err := c.holds(keys, before, after)
+		if err != nil || !ok {
+			return ErrInvariantViolated{Name: inv.name}
+		}
+	}
+	return nil
+}
+
+func (c *invariantClause) holds(keys []string, before map[string][]byte, after map[string]*operations.Value) (bool, error) {
+	each := c.left
+	if c.right.fn == "each" {
+		each = c.right
+	}
+
+	if each.fn != "each" {
+		l, err := c.left.eval(keys, before, after)
+		if err != nil {
+			return false, err
+		}
+		r, err := c.right.eval(keys, before, after)
+		if err != nil {
+			return false, err
+		}
+		return compare(l, r, c.cmp), nil
+	}
+
+	for _, k := range keys {
+		if !each.pattern.MatchString(k) {
+			continue
+		}
+
+		v, err := after[k].Float()
+		if err != nil {
+			return false, err
+		}
+
+		l, r := v.Float, v.Float
+		if c.left == each {
+			r, err = c.right.eval(keys, before, after)
+		} else {
+			l, err = c.left.eval(keys, before, after)
+		}
+		if err != nil {
+			return false, err
+		}
+
+		if !compare(l, r, c.cmp) {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+func (o *invariantOperand) eval(keys []string, before map[string][]byte, after map[string]*operations.Value) (*big.Float, error) {
+	if o.fn == "" {
+		return o.number, nil
+	}
+
+	result := new(big.Float)
+	for _, k := range keys {
+		if !o.pattern.MatchString(k) {
+			continue
+		}
+
+		switch o.fn {
+		case "count":
+			result.Add(result, big.NewFloat(1))
+		case "sum", "delta":
+			v, err := after[k].Float()
+			if err != nil {
+				return nil, err
+			}
+			result.Add(result, v.Float)
+
+			if o.fn == "delta" {
+				old := encoding.NewFloat()
+				if err = old.UnmarshalBinary(before[k]); err != nil {
+					return nil, err
+				}
+				result.Sub(result, old.Float)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+func compare(l, r *big.Float, cmp string) bool {
+	c := l.Cmp(r)
+	switch cmp {
+	case "==":
+		return c == 0
+	case "!=":
+		return c != 0
+	case "<=":
+		return c <= 0
+	case ">=":
+		return c >= 0
+	case "<":
+		return c < 0
+	default:
+		return c > 0
+	}
+}
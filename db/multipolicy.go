@@ -0,0 +1,164 @@
+package db
+
+import (
+	"errors"
+
+	"gitlab.com/SporeDB/sporedb/db/operations"
+)
+
+// A spore may span keys governed by several policies: its main policy, and
+// the additional ones it lists. Each operation is governed by the policy it
+// names, or by the main policy of the spore. Specifications, invariants and
+// size accounting are checked per policy, on the keys it governs, and the
+// spore is only applied once the quorum of every involved policy is reached.
+
+// Error messages for spores spanning several policies.
+var (
+	ErrOpPolicyNotInvolved = errors.New("the operation policy is not listed by the spore")
+	ErrOpPolicyMismatch    = errors.New("the same key is governed by several policies of the spore")
+)
+
+// PolicyUuids returns the uuids of the policies involved in the spore, the main one first.
+func (s *Spore) PolicyUuids() []string {
+	uuids := []string{s.Policy}
+	seen := map[string]bool{s.Policy: true}
+
+	for _, uuid := range s.Policies {
+		if !seen[uuid] {
+			seen[uuid] = true
+			uuids = append(uuids, uuid)
+		}
+	}
+	return uuids
+}
+
+// involves returns true if the policy is involved in the spore.
+func (s *Spore) involves(policy string) bool {
+	for _, uuid := range s.PolicyUuids() {
+		if uuid == policy {
+			return true
+		}
+	}
+	return false
+}
+
+// opPolicy returns the uuid of the policy governing the operation.
+func (s *Spore) opPolicy(o *Operation) string {
+	if o.Policy == "" {
+		return s.Policy
+	}
+	return o.Policy
+}
+
+// getPolicies returns the policies involved in the spore, or nil if one is unknown.
+func (db *DB) getPolicies(s *Spore) []*Policy {
+	uuids := s.PolicyUuids()
+	policies := make([]*Policy, len(uuids))
+
+	for i, uuid := range uuids {
+		if policies[i] = db.getPolicy(uuid); policies[i] == nil {
+			return nil
+		}
+	}
+	return policies
+}
+
+// isEndorser returns true if the identity is an endorser of the policy.
+func (db *DB) isEndorser(p *Policy, identity string) bool {
+	if identity == db.Identity {
+		identity = "" // local endorsement case
+	}
+
+	pub, _, err := db.KeyRing.GetPublic(identity)
+	return err == nil && p.pubToEndorser(pub) != nil
+}
+
+// isEndorserOfAny returns true if the identity is an endorser of one of the policies.
+func (db *DB) isEndorserOfAny(policies []*Policy, identity string) bool {
+	for _, p := range policies {
+		if db.isEndorser(p, identity) {
+			return true
+		}
+	}
+	return false
+}
+
+// pubToAnyEndorser returns true if the public key is an endorser of one of the policies.
+func pubToAnyEndorser(policies []*Policy, pub []byte) bool {
+	for _, p := range policies {
+		if p.pubToEndorser(pub) != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// triggerPolicies returns the policies the trigger's spore has been endorsed with,
+// or its current policies for waiting spores, which are not bound to any policy yet.
+func (db *DB) triggerPolicies(t *dbTrigger) []*Policy {
+	if t.policies != nil {
+		return t.policies
+	}
+	return db.getPolicies(t.spore)
+}
+
+// quorumReached returns true if the endorsements reach the quorum of every policy.
+func (db *DB) quorumReached(policies []*Policy, endorsements []*Endorsement) bool {
+	for _, p := range policies {
+		var n uint64
+		for _, e := range endorsements {
+			if db.isEndorser(p, e.Emitter) {
+				n++
+			}
+		}
+
+		if n < p.Quorum {
+			return false
+		}
+	}
+	return true
+}
+
+// policyValues holds the values of the keys governed by one policy of a spore.
+type policyValues struct {
+	before  map[string][]byte
+	after   map[string]*operations.Value
+	oldSize uint64
+	newSize uint64
+}
+
+// splitValues splits the values of the keys touched by the spore by governing policy.
+// It returns ErrOpPolicyMismatch if a key is governed by several policies.
+func (s *Spore) splitValues(before map[string][]byte, after map[string]*operations.Value) (map[string]*policyValues, error) {
+	split := make(map[string]*policyValues)
+	for _, uuid := range s.PolicyUuids() {
+		split[uuid] = &policyValues{
+			before: make(map[string][]byte),
+			after:  make(map[string]*operations.Value),
+		}
+	}
+
+	governed := make(map[string]string)
+	for _, op := range s.Operations {
+		policy := s.opPolicy(op)
+		pv, ok := split[policy]
+		if !ok {
+			return nil, ErrOpPolicyNotInvolved
+		}
+
+		if p, ok := governed[op.Key]; ok {
+			if p != policy {
+				return nil, ErrOpPolicyMismatch
+			}
+			continue
+		}
+
+		governed[op.Key] = policy
+		pv.before[op.Key] = before[op.Key]
+		pv.after[op.Key] = after[op.Key]
+		pv.oldSize += uint64(len(before[op.Key]))
+		pv.newSize += uint64(len(after[op.Key].Raw))
+	}
+
+	return split, nil
+}
@@ -0,0 +1,91 @@
+package db
+
+// Endorsements may be received before their spore. Such orphan endorsements
+// are buffered in a bounded pool keyed by uuid, and registered as soon as
+// their spore is endorsed. Their signature is only checked at this time, and
+// they are relayed once registered.
+const maxOrphanSpores = 1024
+const maxOrphanEndorsements = 64 // per spore
+
+// addOrphan buffers the endorsement of an unknown spore.
+// It is thread-safe.
+func (db *DB) addOrphan(e *Endorsement) {
+	if db.isDone(e.Uuid) {
+		return // late endorsement
+	}
+
+	db.orphansMutex.Lock()
+	var endorsements []*Endorsement
+	if raw, ok := db.orphans.Get(e.Uuid); ok {
+		endorsements = raw.([]*Endorsement)
+	}
+
+	duplicated := false
+	for _, e2 := range endorsements {
+		duplicated = duplicated || e2.Emitter == e.Emitter
+	}
+
+	if !duplicated && len(endorsements) < maxOrphanEndorsements {
+		db.orphans.Add(e.Uuid, append(endorsements, e))
+	}
+	db.orphansMutex.Unlock()
+
+	// The spore may have been endorsed or promoted meanwhile, missing this endorsement
+	if db.PendingSpore(e.Uuid) != nil {
+		db.adoptOrphans(e.Uuid)
+	}
+}
+
+// adoptOrphans registers the buffered endorsements of the spore.
+// It is thread-safe.
+func (db *DB) adoptOrphans(uuid string) {
+	db.orphansMutex.Lock()
+	raw, ok := db.orphans.Get(uuid)
+	db.orphans.Remove(uuid)
+	db.orphansMutex.Unlock()
+
+	if !ok {
+		return
+	}
+
+	for _, e := range raw.([]*Endorsement) {
+		if db.AddEndorsement(e) == nil {
+			db.Messages <- e // relay the endorsement, as done for known spores
+		}
+	}
+}
+
+// Knows returns true if the spore is pending, or has been recently applied or vetoed.
+// It is thread-safe.
+func (db *DB) Knows(uuid string) bool {
+	return db.PendingSpore(uuid) != nil || db.isDone(uuid)
+}
+
+// isDone returns true if the spore has been recently applied or vetoed.
+// It is thread-safe.
+func (db *DB) isDone(uuid string) bool {
+	db.appliedMutex.Lock()
+	defer db.appliedMutex.Unlock()
+	_, applied := db.applied[uuid]
+	_, vetoed := db.vetoed[uuid]
+	return applied || vetoed
+}
+
+// PendingSpore returns the spore if it is staged or waiting, nil otherwise.
+// It is thread-safe.
+func (db *DB) PendingSpore(uuid string) *Spore {
+	db.waitingMutex.RLock()
+	defer db.waitingMutex.RUnlock()
+	db.stagingMutex.RLock()
+	defer db.stagingMutex.RUnlock()
+
+	if t, ok := db.staging[uuid]; ok {
+		return t.spore
+	}
+
+	if t, ok := db.waiting[uuid]; ok {
+		return t.spore
+	}
+
+	return nil
+}
@@ -3,8 +3,13 @@ package db
 import (
 	"bytes"
 	"errors"
+	"fmt"
 	"regexp"
+	"regexp/syntax"
 	"strings"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
 
 	"gitlab.com/SporeDB/sporedb/db/encoding"
 	"gitlab.com/SporeDB/sporedb/db/operations"
@@ -35,15 +40,25 @@ var NonePolicy = &Policy{
 
 // Check checks that a given operation is valid given its simulation and its database policy.
 func (db *DB) Check(policy string, o *Operation, value *operations.Value) error {
-	p := db.policies[policy]
+	db.policiesMutex.RLock()
+	p, c := db.policies[policy], db.policiesComp[policy]
+	db.policiesMutex.RUnlock()
+
 	if p == nil {
 		return ErrUnknownPolicy
 	}
 
 	if strings.HasPrefix(o.Key, InternalKeyPrefix) {
+		if policy == MetaPolicy && strings.HasPrefix(o.Key, policyKeyPrefix) {
+			return db.checkPolicyUpdate(o, value)
+		}
 		return ErrOpSystemKey
 	}
 
+	if err := db.checkOwner(policy, o.Key); err != nil {
+		return err
+	}
+
 	// Check simulation size
 	l := uint64(len(value.Raw))
 	if p.MaxOpSize > 0 && l > p.MaxOpSize {
@@ -52,10 +67,13 @@ func (db *DB) Check(policy string, o *Operation, value *operations.Value) error
 
 	var valid bool
 	for i, s := range p.Specs {
-		if db.policiesReg[policy][i].MatchString(o.Key) {
+		if c.regexes[i].MatchString(o.Key) {
 			if err := s.checkOp(o); err != nil {
 				return err
 			}
+			if err := c.rules[i].check(value); err != nil {
+				return err
+			}
 			valid = true
 		}
 	}
@@ -70,7 +88,7 @@ func (db *DB) getCurrentPolicyUsage(policy string) (usage, quota uint64) {
 	size, _, _ := db.Store.Get(globalPolicySizeKeyPrefix + "/" + policy)
 	float, _ := operations.NewValue(size).Float()
 	usage, _ = float.Uint64()
-	quota = db.policies[policy].MaxSize
+	quota = db.getPolicy(policy).GetMaxSize()
 	return
 }
 
@@ -98,6 +116,44 @@ func (db *DB) checkCurrentPolicyUsage(oldSize uint64, policy string, values map[
 	return nil
 }
 
+// compiledPolicy holds the compiled specifications and invariants of a policy.
+type compiledPolicy struct {
+	regexes    []*regexp.Regexp
+	rules      []*valueChecker
+	invariants []*invariant
+}
+
+func (p *Policy) compile() (c *compiledPolicy, err error) {
+	c = &compiledPolicy{}
+	if c.regexes, err = p.compileRegexes(); err != nil {
+		return
+	}
+	if c.rules, err = p.compileRules(); err != nil {
+		return
+	}
+	c.invariants, err = p.compileInvariants()
+	return
+}
+
+// CheckInvariants checks that the values of the keys touched by a spore
+// satisfy every invariant of the policy.
+func (db *DB) CheckInvariants(policy string, before map[string][]byte, after map[string]*operations.Value) error {
+	db.policiesMutex.RLock()
+	c := db.policiesComp[policy]
+	db.policiesMutex.RUnlock()
+
+	if c == nil {
+		return ErrUnknownPolicy
+	}
+
+	for _, inv := range c.invariants {
+		if err := inv.check(before, after); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (p *Policy) compileRegexes() (r []*regexp.Regexp, err error) {
 	for _, s := range p.Specs {
 		if n := s.GetName(); n != "" {
@@ -144,3 +200,204 @@ func (s *OSpec) checkOp(o *Operation) error {
 
 	return ErrOpNotAllowed
 }
+
+// Error messages for policy validation.
+var (
+	ErrPolicyNoUuid            = errors.New("the policy has no uuid")
+	ErrPolicyNoEndorser        = errors.New("the policy has no endorser")
+	ErrPolicyEmptyEndorser     = errors.New("the policy contains an endorser without public key")
+	ErrPolicyDuplicateEndorser = errors.New("the policy contains the same endorser twice")
+	ErrPolicyUnsafeQuorum      = errors.New("the policy quorum is too low to prevent conflicting spores from being applied")
+	ErrPolicyUnreachableQuorum = errors.New("the policy quorum is greater than the number of endorsers")
+	ErrPolicyNoSpec            = errors.New("the policy does not allow any key")
+	ErrPolicyUnreachableCosign = errors.New("the cosign quorum is greater than the number of cosigners")
+)
+
+// Validate checks that the policy is safe and usable.
+//
+// Errors are returned for unusable policies, whereas warnings are returned
+// for suspicious but valid policies, such as overlapping specifications:
+// when several specifications match a key, every one of them must allow
+// the operation.
+func (p *Policy) Validate() (errs []error, warnings []string) {
+	if p.Uuid == "" {
+		errs = append(errs, ErrPolicyNoUuid)
+	}
+
+	// Endorsers and quorum
+	n := uint64(len(p.Endorsers))
+	if n == 0 {
+		errs = append(errs, ErrPolicyNoEndorser)
+	}
+
+	seen := make(map[string]bool)
+	for _, e := range p.Endorsers {
+		if len(e.Public) == 0 {
+			errs = append(errs, ErrPolicyEmptyEndorser)
+			continue
+		}
+		if seen[string(e.Public)] {
+			errs = append(errs, ErrPolicyDuplicateEndorser)
+		}
+		seen[string(e.Public)] = true
+	}
+
+	if n > 0 && 2*p.Quorum <= n {
+		errs = append(errs, ErrPolicyUnsafeQuorum)
+	}
+	if p.Quorum > n {
+		errs = append(errs, ErrPolicyUnreachableQuorum)
+	}
+
+	// Invariants
+	for _, i := range p.Invariants {
+		if _, err := compileInvariant(i); err != nil {
+			errs = append(errs, fmt.Errorf("invariant %q: %v", i.Name, err))
+		}
+	}
+
+	// Specifications
+	if len(p.Specs) == 0 {
+		errs = append(errs, ErrPolicyNoSpec)
+	}
+
+	regexes := make([]*regexp.Regexp, len(p.Specs))
+	for i, s := range p.Specs {
+		r, err := (&Policy{Specs: []*OSpec{s}}).compileRegexes()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("spec #%d: %v", i+1, err))
+			continue
+		}
+		regexes[i] = r[0]
+
+		if s.Rules != nil {
+			if _, err = s.Rules.compile(); err != nil {
+				errs = append(errs, fmt.Errorf("spec #%d: %v", i+1, err))
+			}
+		}
+
+		if s.CosignQuorum > uint64(len(s.Cosigners)) {
+			errs = append(errs, fmt.Errorf("spec #%d: %v", i+1, ErrPolicyUnreachableCosign))
+		}
+	}
+
+	for i := range p.Specs {
+		for j := i + 1; j < len(p.Specs); j++ {
+			if regexes[i] == nil || regexes[j] == nil {
+				continue
+			}
+			if specsOverlap(p.Specs[i], p.Specs[j], regexes[i], regexes[j]) {
+				warnings = append(warnings, fmt.Sprintf("specs #%d (%s) and #%d (%s) may match the same keys",
+					i+1, p.Specs[i].KeyString(), j+1, p.Specs[j].KeyString()))
+			}
+		}
+	}
+
+	return
+}
+
+// MaxTimeout returns the maximum duration between the submission of a spore
+// and its deadline.
+func (p *Policy) MaxTimeout() time.Duration {
+	if d, err := ptypes.Duration(p.GetTimeout()); err == nil && d > 0 {
+		return d
+	}
+	return DefaultMaxTimeout
+}
+
+// MaxDelay returns the maximum duration between the submission of a spore
+// and its not-before time.
+func (p *Policy) MaxDelay() time.Duration {
+	if d, err := ptypes.Duration(p.GetDelay()); err == nil && d > 0 {
+		return d
+	}
+	return DefaultMaxDelay
+}
+
+// SporeTimeout returns the timeout of a new spore under the provided policy.
+// It is the requested timeout, or DefaultTimeout if zero, capped by the policy.
+func (db *DB) SporeTimeout(policy string, requested time.Duration) (time.Duration, error) {
+	p := db.getPolicy(policy)
+	if p == nil {
+		return 0, ErrUnknownPolicy
+	}
+
+	if requested <= 0 {
+		requested = DefaultTimeout
+	}
+
+	if max := p.MaxTimeout(); requested > max {
+		requested = max
+	}
+	return requested, nil
+}
+
+// FaultTolerance returns the maximum number of faulty endorsers
+// the policy can tolerate, while keeping both safety and liveness.
+func (p *Policy) FaultTolerance() int {
+	n, q := len(p.Endorsers), int(p.Quorum)
+	f := n - q
+	if s := 2*q - n - 1; s < f {
+		f = s
+	}
+	if f < 0 {
+		f = 0
+	}
+	return f
+}
+
+// KeyString returns a human-readable representation of the key specification.
+func (s *OSpec) KeyString() string {
+	if n := s.GetName(); n != "" {
+		return "name=" + n
+	}
+	return "regex=" + s.GetRegex()
+}
+
+// specsOverlap returns true if some key may be matched by both specifications.
+// Overlaps involving a name are exact, whereas overlaps between regexes are
+// only excluded when both are anchored with incompatible literal prefixes.
+func specsOverlap(a, b *OSpec, ra, rb *regexp.Regexp) bool {
+	if n := b.GetName(); n != "" && ra.MatchString(n) {
+		return true
+	}
+	if n := a.GetName(); n != "" && rb.MatchString(n) {
+		return true
+	}
+	if a.GetName() != "" || b.GetName() != "" {
+		return false
+	}
+
+	pa, anchoredA := anchoredPrefix(a.GetRegex())
+	pb, anchoredB := anchoredPrefix(b.GetRegex())
+	if !anchoredA || !anchoredB {
+		return true
+	}
+	return strings.HasPrefix(pa, pb) || strings.HasPrefix(pb, pa)
+}
+
+// anchoredPrefix returns the literal prefix of a regex anchored at the
+// beginning of the text.
+func anchoredPrefix(expr string) (prefix string, anchored bool) {
+	re, err := syntax.Parse(expr, syntax.Perl)
+	if err != nil {
+		return
+	}
+	re = re.Simplify()
+
+	if re.Op == syntax.OpBeginText {
+		return "", true
+	}
+	if re.Op != syntax.OpConcat || len(re.Sub) == 0 || re.Sub[0].Op != syntax.OpBeginText {
+		return
+	}
+
+	anchored = true
+	for _, sub := range re.Sub[1:] {
+		if sub.Op != syntax.OpLiteral || sub.Flags&syntax.FoldCase != 0 {
+			break
+		}
+		prefix += string(sub.Rune)
+	}
+	return
+}
@@ -14,15 +14,19 @@ var _ = fmt.Errorf
 var _ = math.Inf
 
 type Policy struct {
-	Uuid        string                     `protobuf:"bytes,1,opt,name=uuid" json:"uuid,omitempty"`
-	Comment     string                     `protobuf:"bytes,2,opt,name=comment" json:"comment,omitempty"`
-	Endorsers   []*Endorser                `protobuf:"bytes,3,rep,name=endorsers" json:"endorsers,omitempty"`
-	Quorum      uint64                     `protobuf:"varint,4,opt,name=quorum" json:"quorum,omitempty"`
-	Timeout     *google_protobuf1.Duration `protobuf:"bytes,5,opt,name=timeout" json:"timeout,omitempty"`
-	GracePeriod *google_protobuf1.Duration `protobuf:"bytes,6,opt,name=grace_period,json=gracePeriod" json:"grace_period,omitempty"`
-	MaxSize     uint64                     `protobuf:"varint,7,opt,name=max_size,json=maxSize" json:"max_size,omitempty"`
-	MaxOpSize   uint64                     `protobuf:"varint,8,opt,name=max_op_size,json=maxOpSize" json:"max_op_size,omitempty"`
-	Specs       []*OSpec                   `protobuf:"bytes,9,rep,name=specs" json:"specs,omitempty"`
+	Uuid         string                     `protobuf:"bytes,1,opt,name=uuid" json:"uuid,omitempty"`
+	Comment      string                     `protobuf:"bytes,2,opt,name=comment" json:"comment,omitempty"`
+	Endorsers    []*Endorser                `protobuf:"bytes,3,rep,name=endorsers" json:"endorsers,omitempty"`
+	Quorum       uint64                     `protobuf:"varint,4,opt,name=quorum" json:"quorum,omitempty"`
+	Timeout      *google_protobuf1.Duration `protobuf:"bytes,5,opt,name=timeout" json:"timeout,omitempty"`
+	GracePeriod  *google_protobuf1.Duration `protobuf:"bytes,6,opt,name=grace_period,json=gracePeriod" json:"grace_period,omitempty"`
+	MaxSize      uint64                     `protobuf:"varint,7,opt,name=max_size,json=maxSize" json:"max_size,omitempty"`
+	MaxOpSize    uint64                     `protobuf:"varint,8,opt,name=max_op_size,json=maxOpSize" json:"max_op_size,omitempty"`
+	Specs        []*OSpec                   `protobuf:"bytes,9,rep,name=specs" json:"specs,omitempty"`
+	Invariants   []*Invariant               `protobuf:"bytes,10,rep,name=invariants" json:"invariants,omitempty"`
+	Claims       []string                   `protobuf:"bytes,11,rep,name=claims" json:"claims,omitempty"`
+	EmitterQuota *EmitterQuota              `protobuf:"bytes,12,opt,name=emitter_quota,json=emitterQuota" json:"emitter_quota,omitempty"`
+	Delay        *google_protobuf1.Duration `protobuf:"bytes,13,opt,name=delay" json:"delay,omitempty"`
 }
 
 func (m *Policy) Reset()                    { *m = Policy{} }
@@ -93,6 +97,34 @@ func (m *Policy) GetSpecs() []*OSpec {
 	return nil
 }
 
+func (m *Policy) GetInvariants() []*Invariant {
+	if m != nil {
+		return m.Invariants
+	}
+	return nil
+}
+
+func (m *Policy) GetClaims() []string {
+	if m != nil {
+		return m.Claims
+	}
+	return nil
+}
+
+func (m *Policy) GetEmitterQuota() *EmitterQuota {
+	if m != nil {
+		return m.EmitterQuota
+	}
+	return nil
+}
+
+func (m *Policy) GetDelay() *google_protobuf1.Duration {
+	if m != nil {
+		return m.Delay
+	}
+	return nil
+}
+
 type Endorser struct {
 	Public  []byte `protobuf:"bytes,1,opt,name=public,proto3" json:"public,omitempty"`
 	Comment string `protobuf:"bytes,2,opt,name=comment" json:"comment,omitempty"`
@@ -124,6 +156,11 @@ type OSpec struct {
 	Key               isOSpec_Key    `protobuf_oneof:"key"`
 	MaxSize           uint64         `protobuf:"varint,4,opt,name=max_size,json=maxSize" json:"max_size,omitempty"`
 	AllowedOperations []Operation_Op `protobuf:"varint,5,rep,packed,name=allowed_operations,json=allowedOperations,enum=db.Operation_Op" json:"allowed_operations,omitempty"`
+	Rules             *ValueRules    `protobuf:"bytes,6,opt,name=rules" json:"rules,omitempty"`
+	// Parties whose signature is required to modify the matching keys, among
+	// the emitter and the cosigners of the spore.
+	Cosigners    []*Endorser `protobuf:"bytes,7,rep,name=cosigners" json:"cosigners,omitempty"`
+	CosignQuorum uint64      `protobuf:"varint,8,opt,name=cosign_quorum,json=cosignQuorum" json:"cosign_quorum,omitempty"`
 }
 
 func (m *OSpec) Reset()                    { *m = OSpec{} }
@@ -180,6 +217,27 @@ func (m *OSpec) GetAllowedOperations() []Operation_Op {
 	return nil
 }
 
+func (m *OSpec) GetRules() *ValueRules {
+	if m != nil {
+		return m.Rules
+	}
+	return nil
+}
+
+func (m *OSpec) GetCosigners() []*Endorser {
+	if m != nil {
+		return m.Cosigners
+	}
+	return nil
+}
+
+func (m *OSpec) GetCosignQuorum() uint64 {
+	if m != nil {
+		return m.CosignQuorum
+	}
+	return 0
+}
+
 // XXX_OneofFuncs is for the internal use of the proto package.
 func (*OSpec) XXX_OneofFuncs() (func(msg proto.Message, b *proto.Buffer) error, func(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error), func(msg proto.Message) (n int), []interface{}) {
 	return _OSpec_OneofMarshaler, _OSpec_OneofUnmarshaler, _OSpec_OneofSizer, []interface{}{
@@ -246,38 +304,185 @@ func _OSpec_OneofSizer(msg proto.Message) (n int) {
 	return n
 }
 
+// ValueRules constrain the simulated value of a key.
+// Empty rules are not checked.
+type ValueRules struct {
+	Numeric    bool   `protobuf:"varint,1,opt,name=numeric" json:"numeric,omitempty"`
+	Min        string `protobuf:"bytes,2,opt,name=min" json:"min,omitempty"`
+	Max        string `protobuf:"bytes,3,opt,name=max" json:"max,omitempty"`
+	Regex      string `protobuf:"bytes,4,opt,name=regex" json:"regex,omitempty"`
+	JsonSchema string `protobuf:"bytes,5,opt,name=json_schema,json=jsonSchema" json:"json_schema,omitempty"`
+	MaxMembers uint64 `protobuf:"varint,6,opt,name=max_members,json=maxMembers" json:"max_members,omitempty"`
+}
+
+func (m *ValueRules) Reset()                    { *m = ValueRules{} }
+func (m *ValueRules) String() string            { return proto.CompactTextString(m) }
+func (*ValueRules) ProtoMessage()               {}
+func (*ValueRules) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{3} }
+
+func (m *ValueRules) GetNumeric() bool {
+	if m != nil {
+		return m.Numeric
+	}
+	return false
+}
+
+func (m *ValueRules) GetMin() string {
+	if m != nil {
+		return m.Min
+	}
+	return ""
+}
+
+func (m *ValueRules) GetMax() string {
+	if m != nil {
+		return m.Max
+	}
+	return ""
+}
+
+func (m *ValueRules) GetRegex() string {
+	if m != nil {
+		return m.Regex
+	}
+	return ""
+}
+
+func (m *ValueRules) GetJsonSchema() string {
+	if m != nil {
+		return m.JsonSchema
+	}
+	return ""
+}
+
+func (m *ValueRules) GetMaxMembers() uint64 {
+	if m != nil {
+		return m.MaxMembers
+	}
+	return 0
+}
+
+// Invariant is a named condition that must hold after every spore of the policy.
+// See db/invariant.go for the expression syntax.
+type Invariant struct {
+	Name       string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	Expression string `protobuf:"bytes,2,opt,name=expression" json:"expression,omitempty"`
+}
+
+func (m *Invariant) Reset()                    { *m = Invariant{} }
+func (m *Invariant) String() string            { return proto.CompactTextString(m) }
+func (*Invariant) ProtoMessage()               {}
+func (*Invariant) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{4} }
+
+func (m *Invariant) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Invariant) GetExpression() string {
+	if m != nil {
+		return m.Expression
+	}
+	return ""
+}
+
+// EmitterQuota limits the resources used by each emitter of a policy.
+// Zero values disable the related limit.
+type EmitterQuota struct {
+	MaxSize   uint64                     `protobuf:"varint,1,opt,name=max_size,json=maxSize" json:"max_size,omitempty"`
+	MaxSpores uint64                     `protobuf:"varint,2,opt,name=max_spores,json=maxSpores" json:"max_spores,omitempty"`
+	Window    *google_protobuf1.Duration `protobuf:"bytes,3,opt,name=window" json:"window,omitempty"`
+	MaxStaged uint64                     `protobuf:"varint,4,opt,name=max_staged,json=maxStaged" json:"max_staged,omitempty"`
+}
+
+func (m *EmitterQuota) Reset()                    { *m = EmitterQuota{} }
+func (m *EmitterQuota) String() string            { return proto.CompactTextString(m) }
+func (*EmitterQuota) ProtoMessage()               {}
+func (*EmitterQuota) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{5} }
+
+func (m *EmitterQuota) GetMaxSize() uint64 {
+	if m != nil {
+		return m.MaxSize
+	}
+	return 0
+}
+
+func (m *EmitterQuota) GetMaxSpores() uint64 {
+	if m != nil {
+		return m.MaxSpores
+	}
+	return 0
+}
+
+func (m *EmitterQuota) GetWindow() *google_protobuf1.Duration {
+	if m != nil {
+		return m.Window
+	}
+	return nil
+}
+
+func (m *EmitterQuota) GetMaxStaged() uint64 {
+	if m != nil {
+		return m.MaxStaged
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterType((*Policy)(nil), "db.Policy")
 	proto.RegisterType((*Endorser)(nil), "db.Endorser")
 	proto.RegisterType((*OSpec)(nil), "db.OSpec")
+	proto.RegisterType((*ValueRules)(nil), "db.ValueRules")
+	proto.RegisterType((*Invariant)(nil), "db.Invariant")
+	proto.RegisterType((*EmitterQuota)(nil), "db.EmitterQuota")
 }
 
 func init() { proto.RegisterFile("db/policy.proto", fileDescriptor1) }
 
 var fileDescriptor1 = []byte{
-	// 384 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x51, 0xdd, 0x6a, 0xdb, 0x30,
-	0x18, 0xad, 0x13, 0x3b, 0x89, 0xbf, 0x84, 0x6e, 0x13, 0xa3, 0xa8, 0xbd, 0xe8, 0x4c, 0xae, 0xcc,
-	0x2e, 0x1c, 0x48, 0x6f, 0x0b, 0x83, 0xb1, 0x41, 0xef, 0x52, 0xd4, 0x07, 0x08, 0xb6, 0xf5, 0xcd,
-	0x88, 0x59, 0x96, 0x26, 0x59, 0xcc, 0xed, 0x93, 0xec, 0x95, 0xf6, 0x56, 0x23, 0x92, 0xbd, 0x91,
-	0x8b, 0xd1, 0x3b, 0x9f, 0x1f, 0x7d, 0x9c, 0x73, 0x0c, 0x6f, 0x78, 0xb5, 0xd3, 0xaa, 0x15, 0xf5,
-	0x73, 0xa1, 0x8d, 0xea, 0x15, 0x99, 0xf1, 0xea, 0xe6, 0x92, 0x57, 0x3b, 0xab, 0x95, 0xc1, 0xc0,
-	0xdd, 0xdc, 0x36, 0x4a, 0x35, 0x2d, 0xee, 0x3c, 0xaa, 0xdc, 0xb7, 0x1d, 0x77, 0xa6, 0xec, 0x85,
-	0xea, 0x82, 0xbe, 0xfd, 0x3d, 0x83, 0xc5, 0xa3, 0x3f, 0x42, 0x08, 0xc4, 0xce, 0x09, 0x4e, 0xa3,
-	0x2c, 0xca, 0x53, 0xe6, 0xbf, 0x09, 0x85, 0x65, 0xad, 0xa4, 0xc4, 0xae, 0xa7, 0x33, 0x4f, 0x4f,
-	0x90, 0x7c, 0x84, 0x14, 0x3b, 0xae, 0x8c, 0x45, 0x63, 0xe9, 0x3c, 0x9b, 0xe7, 0xeb, 0xfd, 0xa6,
-	0xe0, 0x55, 0xf1, 0x75, 0x24, 0xd9, 0x3f, 0x99, 0x5c, 0xc1, 0xe2, 0x87, 0x53, 0xc6, 0x49, 0x1a,
-	0x67, 0x51, 0x1e, 0xb3, 0x11, 0x91, 0x3b, 0x58, 0xf6, 0x42, 0xa2, 0x72, 0x3d, 0x4d, 0xb2, 0x28,
-	0x5f, 0xef, 0xaf, 0x8b, 0x10, 0xb7, 0x98, 0xe2, 0x16, 0x5f, 0xc6, 0xb8, 0x6c, 0x72, 0x92, 0x7b,
-	0xd8, 0x34, 0xa6, 0xac, 0xf1, 0xa8, 0xd1, 0x08, 0xc5, 0xe9, 0xe2, 0xb5, 0x97, 0x6b, 0x6f, 0x7f,
-	0xf4, 0x6e, 0x72, 0x0d, 0x2b, 0x59, 0x0e, 0x47, 0x2b, 0x5e, 0x90, 0x2e, 0x7d, 0x98, 0xa5, 0x2c,
-	0x87, 0x27, 0xf1, 0x82, 0xe4, 0x16, 0xd6, 0x27, 0x49, 0xe9, 0xa0, 0xae, 0xbc, 0x9a, 0xca, 0x72,
-	0x38, 0x68, 0xaf, 0x7f, 0x80, 0xc4, 0x6a, 0xac, 0x2d, 0x4d, 0x7d, 0xdb, 0xf4, 0xd4, 0xf6, 0xf0,
-	0xa4, 0xb1, 0x66, 0x81, 0xdf, 0xde, 0xc3, 0x6a, 0x6a, 0x7f, 0xaa, 0xac, 0x5d, 0xd5, 0x8a, 0xda,
-	0xcf, 0xb9, 0x61, 0x23, 0xfa, 0xff, 0xa0, 0xdb, 0x5f, 0x11, 0x24, 0xfe, 0x1c, 0x79, 0x0f, 0x71,
-	0x57, 0x4a, 0x0c, 0x3f, 0xe2, 0xe1, 0x82, 0x79, 0x44, 0xae, 0x20, 0x31, 0xd8, 0xe0, 0x10, 0xde,
-	0x3d, 0x5c, 0xb0, 0x00, 0xcf, 0x1a, 0xc5, 0xe7, 0x8d, 0x3e, 0x01, 0x29, 0xdb, 0x56, 0xfd, 0x44,
-	0x7e, 0x54, 0x1a, 0xc3, 0x1c, 0x96, 0x26, 0xd9, 0x3c, 0xbf, 0xdc, 0xbf, 0xf5, 0xf1, 0x27, 0xb6,
-	0x38, 0x68, 0xf6, 0x6e, 0xf4, 0xfe, 0x25, 0xed, 0xe7, 0x04, 0xe6, 0xdf, 0xf1, 0xb9, 0x5a, 0xf8,
-	0x51, 0xef, 0xfe, 0x04, 0x00, 0x00, 0xff, 0xff, 0xe4, 0x17, 0xcb, 0x76, 0x72, 0x02, 0x00, 0x00,
+	// 663 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x94, 0xcf, 0x6b, 0xe3, 0x46,
+	0x14, 0xc7, 0x63, 0x4b, 0xb2, 0xad, 0x67, 0x3b, 0x4d, 0x87, 0x10, 0x26, 0x81, 0x26, 0xc6, 0xed,
+	0xc1, 0x14, 0x6a, 0xd3, 0x84, 0xde, 0x02, 0x81, 0xd2, 0x42, 0x7a, 0x28, 0x4e, 0xc6, 0xd0, 0xab,
+	0x19, 0x49, 0xaf, 0xee, 0x74, 0x35, 0x1a, 0x45, 0x23, 0x6d, 0x94, 0xfc, 0x13, 0x7b, 0xdf, 0xe3,
+	0xfe, 0x95, 0x7b, 0x5c, 0x66, 0x46, 0xf2, 0x0f, 0xd8, 0x25, 0x37, 0x7d, 0x3f, 0xef, 0xcd, 0xf8,
+	0xfd, 0xf8, 0x8e, 0xe1, 0xbb, 0x24, 0x5a, 0xe4, 0x2a, 0x15, 0xf1, 0xcb, 0x3c, 0x2f, 0x54, 0xa9,
+	0x48, 0x37, 0x89, 0x2e, 0x8e, 0x93, 0x68, 0xa1, 0x73, 0x55, 0xa0, 0x63, 0x17, 0x97, 0x1b, 0xa5,
+	0x36, 0x29, 0x2e, 0xac, 0x8a, 0xaa, 0x7f, 0x17, 0x49, 0x55, 0xf0, 0x52, 0xa8, 0xcc, 0xc5, 0xa7,
+	0x9f, 0x3d, 0xe8, 0x3d, 0xd8, 0x4b, 0x08, 0x01, 0xbf, 0xaa, 0x44, 0x42, 0x3b, 0x93, 0xce, 0x2c,
+	0x64, 0xf6, 0x9b, 0x50, 0xe8, 0xc7, 0x4a, 0x4a, 0xcc, 0x4a, 0xda, 0xb5, 0xb8, 0x95, 0xe4, 0x67,
+	0x08, 0x31, 0x4b, 0x54, 0xa1, 0xb1, 0xd0, 0xd4, 0x9b, 0x78, 0xb3, 0xe1, 0xf5, 0x68, 0x9e, 0x44,
+	0xf3, 0x3f, 0x1b, 0xc8, 0x76, 0x61, 0x72, 0x06, 0xbd, 0xa7, 0x4a, 0x15, 0x95, 0xa4, 0xfe, 0xa4,
+	0x33, 0xf3, 0x59, 0xa3, 0xc8, 0x0d, 0xf4, 0x4b, 0x21, 0x51, 0x55, 0x25, 0x0d, 0x26, 0x9d, 0xd9,
+	0xf0, 0xfa, 0x7c, 0xee, 0xca, 0x9d, 0xb7, 0xe5, 0xce, 0xff, 0x68, 0xca, 0x65, 0x6d, 0x26, 0xb9,
+	0x85, 0xd1, 0xa6, 0xe0, 0x31, 0xae, 0x73, 0x2c, 0x84, 0x4a, 0x68, 0xef, 0xad, 0x93, 0x43, 0x9b,
+	0xfe, 0x60, 0xb3, 0xc9, 0x39, 0x0c, 0x24, 0xaf, 0xd7, 0x5a, 0xbc, 0x22, 0xed, 0xdb, 0x62, 0xfa,
+	0x92, 0xd7, 0x2b, 0xf1, 0x8a, 0xe4, 0x12, 0x86, 0x26, 0xa4, 0x72, 0x17, 0x1d, 0xd8, 0x68, 0x28,
+	0x79, 0xbd, 0xcc, 0x6d, 0xfc, 0x0a, 0x02, 0x9d, 0x63, 0xac, 0x69, 0x68, 0xbb, 0x0d, 0x4d, 0xb7,
+	0xcb, 0x55, 0x8e, 0x31, 0x73, 0x9c, 0xfc, 0x02, 0x20, 0xb2, 0xf7, 0xbc, 0x10, 0x3c, 0x2b, 0x35,
+	0x05, 0x9b, 0x35, 0x36, 0x59, 0x7f, 0xb5, 0x94, 0xed, 0x25, 0x98, 0xa9, 0xc4, 0x29, 0x17, 0x52,
+	0xd3, 0xe1, 0xc4, 0x9b, 0x85, 0xac, 0x51, 0xe4, 0x37, 0x18, 0xa3, 0x14, 0x65, 0x89, 0xc5, 0xfa,
+	0xa9, 0x52, 0x25, 0xa7, 0x23, 0xdb, 0xe1, 0x89, 0x9d, 0xae, 0x0b, 0x3c, 0x1a, 0xce, 0x46, 0xb8,
+	0xa7, 0xc8, 0x02, 0x82, 0x04, 0x53, 0xfe, 0x42, 0xc7, 0x6f, 0x0d, 0xc4, 0xe5, 0x4d, 0x6f, 0x61,
+	0xd0, 0x2e, 0xcb, 0xd4, 0x92, 0x57, 0x51, 0x2a, 0x62, 0xbb, 0xfd, 0x11, 0x6b, 0xd4, 0xb7, 0xf7,
+	0x3f, 0xfd, 0xd0, 0x85, 0xc0, 0x76, 0x4f, 0x4e, 0xc1, 0xcf, 0xb8, 0x44, 0xe7, 0x9b, 0xfb, 0x23,
+	0x66, 0x15, 0x39, 0x83, 0xa0, 0xc0, 0x0d, 0xd6, 0xee, 0xdc, 0xfd, 0x11, 0x73, 0xf2, 0x60, 0x01,
+	0xfe, 0xe1, 0x02, 0xee, 0x80, 0xf0, 0x34, 0x55, 0xcf, 0x98, 0xac, 0x55, 0x8e, 0xae, 0x58, 0x4d,
+	0x83, 0x89, 0x37, 0x3b, 0x76, 0xdd, 0x2f, 0x5b, 0x3a, 0x5f, 0xe6, 0xec, 0xfb, 0x26, 0x77, 0x0b,
+	0x35, 0xf9, 0x09, 0x82, 0xa2, 0x4a, 0x51, 0x37, 0x9e, 0x38, 0x36, 0x67, 0xfe, 0xe1, 0x69, 0x85,
+	0xcc, 0x50, 0xe6, 0x82, 0xc6, 0xb9, 0xb1, 0xd2, 0x62, 0x93, 0x19, 0xe7, 0xf6, 0xbf, 0xe6, 0xdc,
+	0x6d, 0x98, 0xfc, 0x08, 0x63, 0x27, 0xd6, 0x8d, 0x81, 0x9d, 0x2b, 0x46, 0x0e, 0x3e, 0x5a, 0xf6,
+	0x7b, 0x00, 0xde, 0x3b, 0x7c, 0x99, 0x7e, 0xea, 0x00, 0xec, 0x7e, 0xcd, 0x8c, 0x2e, 0xab, 0x24,
+	0x16, 0xcd, 0x4c, 0x07, 0xac, 0x95, 0xe4, 0x04, 0x3c, 0x29, 0xb2, 0x66, 0xa0, 0xe6, 0xd3, 0x12,
+	0x5e, 0x53, 0xaf, 0x21, 0xbc, 0x26, 0xa7, 0xed, 0xf8, 0x7c, 0xcb, 0x9c, 0x20, 0x57, 0x30, 0xfc,
+	0x5f, 0xab, 0x6c, 0xad, 0xe3, 0xff, 0x50, 0x72, 0xfb, 0x68, 0x42, 0x06, 0x06, 0xad, 0x2c, 0x31,
+	0x09, 0x66, 0xba, 0x12, 0x65, 0x84, 0x85, 0x9b, 0x83, 0xcf, 0x40, 0xf2, 0xfa, 0x6f, 0x47, 0xa6,
+	0x77, 0x10, 0x6e, 0xdd, 0x68, 0x5e, 0xfc, 0x6e, 0x73, 0xcd, 0xde, 0x2e, 0x01, 0xb0, 0xce, 0x0b,
+	0xd4, 0x5a, 0xa8, 0xb6, 0xc6, 0x3d, 0x32, 0xfd, 0xd8, 0x81, 0xd1, 0xbe, 0x0b, 0x0f, 0x16, 0xda,
+	0x39, 0x5c, 0xe8, 0x0f, 0x00, 0x36, 0x64, 0xfe, 0x8f, 0x34, 0xed, 0x6e, 0x1f, 0xd4, 0xca, 0x02,
+	0xf2, 0x2b, 0xf4, 0x9e, 0x45, 0x96, 0xa8, 0x67, 0xea, 0xbd, 0x65, 0xd9, 0x26, 0x71, 0x7b, 0x63,
+	0xc9, 0x37, 0x98, 0x50, 0x7f, 0x77, 0xa3, 0x05, 0x51, 0xcf, 0x9e, 0xbc, 0xf9, 0x32, 0x00, 0x2f,
+	0xdd, 0x32, 0xd9, 0x1b, 0x05, 0x00, 0x00,
 }
@@ -4,21 +4,52 @@ import (
 	"testing"
 	"time"
 
+	"github.com/golang/protobuf/ptypes"
 	"github.com/stretchr/testify/require"
+
+	"gitlab.com/SporeDB/sporedb/db/operations"
+	"gitlab.com/SporeDB/sporedb/myc/sec"
 )
 
 func getTestSpore(db *DB) (s *Spore, sign func()) {
 	s = NewSpore()
 	s.SetTimeout(100 * time.Millisecond)
 	s.Emitter = db.Identity
-	sign = func() {
-		db.cache.Purge()
-		s.Signature = nil
-		s.Signature, _ = db.KeyRing.Sign(db.HashSpore(s))
-	}
+	sign = func() { signTestSpore(db, s) }
 	return
 }
 
+// getTestPolicySpore returns a spore of the policy with the operations, signed by the node.
+func getTestPolicySpore(db *DB, policy string, ops ...*Operation) *Spore {
+	s, sign := getTestSpore(db)
+	s.SetTimeout(time.Second)
+	s.Policy = policy
+	s.Operations = ops
+	sign()
+	return s
+}
+
+// signTestSpore signs the spore again, after a change.
+func signTestSpore(db *DB, s *Spore) {
+	db.cache.Purge()
+	s.Signature = nil
+	s.Signature, _ = db.KeyRing.Sign(hashMessage(s))
+}
+
+// setOp returns a SET operation of the key.
+func setOp(key, data string) *Operation {
+	return &Operation{Key: key, Op: Operation_SET, Data: []byte(data)}
+}
+
+// endorseAs adds the endorsement of the spore by the identity, signed with its keyring.
+func endorseAs(db *DB, k sec.KeyRing, identity string, s *Spore) error {
+	signature, err := k.Sign(db.HashSpore(s))
+	if err != nil {
+		return err
+	}
+	return db.AddEndorsement(&Endorsement{Emitter: identity, Uuid: s.Uuid, Signature: signature})
+}
+
 func TestDB_PolicySize(t *testing.T) {
 	db, done := getTestingDB(t)
 	defer done()
@@ -63,3 +94,334 @@ func TestDB_PolicySize(t *testing.T) {
 	sign()
 	require.Nil(t, db.Endorse(s), "operations that comply to policy quota must be allowed")
 }
+
+func TestDB_PolicyGovernance(t *testing.T) {
+	db, done := getTestingDB(t)
+	defer done()
+
+	require.Nil(t, db.AddPolicy(&Policy{Uuid: MetaPolicy}))
+	db.Start(false)
+
+	strict := &Policy{
+		Uuid:   "strict",
+		Quorum: 0,
+		Specs:  []*OSpec{{Key: &OSpec_Name{"a"}}},
+	}
+	op, err := NewPolicyOperation(strict)
+	require.Nil(t, err)
+
+	s, sign := getTestSpore(db)
+	s.Operations = []*Operation{op}
+	sign()
+	require.Exactly(t, ErrOpSystemKey, db.Endorse(s), "policy updates must be endorsed under the meta-policy")
+
+	s.Policy = MetaPolicy
+	s.Operations = []*Operation{{Key: PolicyKey("strict"), Data: []byte("garbage")}}
+	sign()
+	require.Exactly(t, ErrInvalidPolicyUpdate, db.Endorse(s), "invalid policies must be refused")
+
+	s.Operations = []*Operation{op}
+	sign()
+	require.Nil(t, db.Endorse(s))
+	require.Exactly(t, "strict", db.getPolicy("strict").Uuid)
+
+	s, sign = getTestSpore(db)
+	s.Policy = MetaPolicy
+	s.Operations = []*Operation{NewPolicyRemoval(MetaPolicy)}
+	sign()
+	require.Exactly(t, ErrInvalidPolicyUpdate, db.Endorse(s), "the meta-policy must not be removable")
+
+	// Policies are loaded from the store at start-up
+	db2 := NewDB(db.Store, "test", db.KeyRing)
+	require.Nil(t, db2.LoadStoredPolicies())
+	require.Exactly(t, "strict", db2.getPolicy("strict").Uuid)
+
+	s, sign = getTestSpore(db)
+	s.Policy = MetaPolicy
+	s.Operations = []*Operation{NewPolicyRemoval("strict")}
+	sign()
+	require.Nil(t, db.Endorse(s))
+	require.Nil(t, db.getPolicy("strict"))
+}
+
+func TestDB_PolicyReload(t *testing.T) {
+	db, done := getTestingDB(t)
+	defer done()
+
+	pub, _, _ := db.KeyRing.GetPublic("")
+	require.Nil(t, db.AddPolicy(&Policy{
+		Uuid:      "reloaded",
+		Quorum:    2,
+		Endorsers: []*Endorser{{Public: pub}},
+		Claims:    []string{"a"},
+		Specs:     []*OSpec{{Key: &OSpec_Regex{".*"}}},
+	}))
+	db.Start(false)
+
+	s, sign := getTestSpore(db)
+	s.Policy = "reloaded"
+	s.Operations = []*Operation{{Key: "a", Op: Operation_SET, Data: []byte("A")}}
+	sign()
+	require.Nil(t, db.Endorse(s))
+
+	db.RemovePolicy("reloaded")
+	require.Exactly(t, []string{"none"}, db.Policies())
+
+	db.stagingMutex.RLock()
+	defer db.stagingMutex.RUnlock()
+	require.NotNil(t, db.staging[s.Uuid], "in-flight spores must be kept")
+	require.Exactly(t, uint64(2), db.staging[s.Uuid].policies[0].Quorum, "in-flight spores must keep their policy")
+}
+
+func TestDB_ReplacePolicies(t *testing.T) {
+	db, done := getTestingDB(t)
+	defer done()
+
+	specs := []*OSpec{{Key: &OSpec_Regex{".*"}}}
+	require.Nil(t, db.AddPolicy(&Policy{Uuid: "a", Claims: []string{"a/"}, Specs: specs}))
+	require.Nil(t, db.AddPolicy(&Policy{Uuid: "b", Claims: []string{"b/"}, Specs: specs}))
+
+	// Invalid sets are rejected as a whole
+	require.Exactly(t, ErrClaimOverlap, db.ReplacePolicies([]string{"a", "b"}, []*Policy{
+		{Uuid: "c", Claims: []string{"c/"}, Specs: specs},
+		{Uuid: "d", Claims: []string{"c/d"}, Specs: specs},
+	}))
+	require.NotNil(t, db.ReplacePolicies([]string{"a", "b"}, []*Policy{
+		{Uuid: "c", Specs: []*OSpec{{Key: &OSpec_Regex{"("}}}},
+	}))
+	require.Exactly(t, []string{"a", "b", "none"}, db.Policies())
+
+	// Claims of removed policies are released
+	require.Nil(t, db.ReplacePolicies([]string{"a", "b"}, []*Policy{
+		{Uuid: "a", Claims: []string{"b/"}, Specs: specs},
+		{Uuid: "c", Claims: []string{"c/"}, Specs: specs},
+	}))
+	require.Exactly(t, []string{"a", "c", "none"}, db.Policies())
+	require.Exactly(t, []string{"b/"}, db.getPolicy("a").Claims)
+}
+
+func TestPolicy_Validate(t *testing.T) {
+	p := &Policy{
+		Uuid:      "test",
+		Quorum:    2,
+		Endorsers: []*Endorser{{Public: []byte("a")}, {Public: []byte("b")}, {Public: []byte("c")}},
+		Specs: []*OSpec{
+			{Key: &OSpec_Regex{"^account/"}},
+			{Key: &OSpec_Regex{"^user/"}},
+		},
+	}
+
+	errs, warnings := p.Validate()
+	require.Empty(t, errs)
+	require.Empty(t, warnings)
+	require.Exactly(t, 0, p.FaultTolerance())
+
+	p.Endorsers = append(p.Endorsers, &Endorser{Public: []byte("d")})
+	errs, _ = p.Validate()
+	require.Exactly(t, []error{ErrPolicyUnsafeQuorum}, errs)
+
+	p.Quorum = 5
+	errs, _ = p.Validate()
+	require.Exactly(t, []error{ErrPolicyUnreachableQuorum}, errs)
+
+	p.Quorum = 3
+	p.Endorsers = append(p.Endorsers, &Endorser{Public: []byte("d")})
+	errs, _ = p.Validate()
+	require.Exactly(t, []error{ErrPolicyDuplicateEndorser}, errs)
+
+	p.Endorsers = p.Endorsers[:4]
+	require.Exactly(t, 1, p.FaultTolerance())
+
+	p.Specs = append(p.Specs,
+		&OSpec{Key: &OSpec_Name{"account/alice"}},
+		&OSpec{Key: &OSpec_Regex{"^user/admin/"}},
+		&OSpec{Key: &OSpec_Regex{"("}},
+	)
+	errs, warnings = p.Validate()
+	require.Len(t, errs, 1)
+	require.Len(t, warnings, 2)
+}
+
+func TestDB_ValueRules(t *testing.T) {
+	db, done := getTestingDB(t)
+	defer done()
+
+	addTestingPolicies(t, db, &Policy{
+		Uuid: "rules",
+		Specs: []*OSpec{
+			{Key: &OSpec_Name{"balance"}, Rules: &ValueRules{Min: "0", Max: "1000"}},
+			{Key: &OSpec_Name{"email"}, Rules: &ValueRules{Regex: "^[^@]+@[^@]+$"}},
+			{Key: &OSpec_Name{"profile"}, Rules: &ValueRules{JsonSchema: `{"type": "object", "required": ["name"]}`}},
+			{Key: &OSpec_Name{"members"}, Rules: &ValueRules{MaxMembers: 2}},
+		},
+	})
+
+	check := func(key string, op Operation_Op, data ...string) error {
+		v := operations.NewValue(nil)
+		var err error
+		for _, d := range data {
+			o := &Operation{Key: key, Op: op, Data: []byte(d)}
+			if err = o.Exec(v); err != nil {
+				return err
+			}
+			err = db.Check("rules", o, v)
+		}
+		return err
+	}
+
+	require.Nil(t, check("balance", Operation_ADD, "10", "990"))
+	require.Exactly(t, ErrValueOutOfRange, check("balance", Operation_ADD, "10", "991"))
+	require.Exactly(t, ErrValueOutOfRange, check("balance", Operation_SET, "-1"))
+	require.Exactly(t, ErrValueNotNumeric, check("balance", Operation_SET, "ten"))
+
+	require.Nil(t, check("email", Operation_SET, "alice@example.org"))
+	require.Exactly(t, ErrValueMismatch, check("email", Operation_SET, "alice"))
+
+	require.Nil(t, check("profile", Operation_SET, `{"name": "Alice"}`))
+	require.NotNil(t, check("profile", Operation_SET, `{"age": 42}`))
+	require.NotNil(t, check("profile", Operation_SET, `not json`))
+
+	require.Nil(t, check("members", Operation_SADD, "alice", "bob"))
+	require.Exactly(t, ErrValueTooManyMembers, check("members", Operation_SADD, "alice", "bob", "carol"))
+
+	require.Exactly(t, ErrInvalidValueRules, db.AddPolicy(&Policy{
+		Uuid:  "invalid",
+		Specs: []*OSpec{{Key: &OSpec_Name{"a"}, Rules: &ValueRules{Min: "10", Max: "1"}}},
+	}))
+}
+
+func TestDB_Invariants(t *testing.T) {
+	db, done := getTestingDB(t)
+	defer done()
+
+	addTestingPolicies(t, db, &Policy{
+		Uuid:  "bank",
+		Specs: []*OSpec{{Key: &OSpec_Regex{".*"}}},
+		Invariants: []*Invariant{
+			{Name: "positive", Expression: "each(balance:*) >= 0"},
+			{Name: "conservation", Expression: "delta(balance:*) == 0 && count(balance:*) <= 2"},
+		},
+	})
+	db.Start(false)
+
+	s, sign := getTestSpore(db)
+	s.Policy = "bank"
+	s.Operations = []*Operation{{Key: "balance:alice", Op: Operation_ADD, Data: []byte("10")}}
+	sign()
+	require.Exactly(t, ErrInvariantViolated{Name: "conservation"}, db.Endorse(s), "money must not be created")
+
+	s.Operations = []*Operation{
+		{Key: "balance:alice", Op: Operation_ADD, Data: []byte("-10")},
+		{Key: "balance:bob", Op: Operation_ADD, Data: []byte("10")},
+	}
+	sign()
+	require.Exactly(t, ErrInvariantViolated{Name: "positive"}, db.Endorse(s), "balances must not go negative")
+
+	before := map[string][]byte{"balance:alice": []byte("10"), "other": nil}
+	after := map[string]*operations.Value{
+		"balance:alice": operations.NewValue([]byte("0")),
+		"balance:bob":   operations.NewValue([]byte("10")),
+		"other":         operations.NewValue([]byte("not a number")),
+	}
+	require.Nil(t, db.CheckInvariants("bank", before, after))
+
+	after["balance:carol"] = operations.NewValue(nil)
+	require.Exactly(t, ErrInvariantViolated{Name: "conservation"}, db.CheckInvariants("bank", before, after))
+
+	for _, expr := range []string{"", "each(a) >= each(b)", "1 + 1 == 2", "max(a) > 0", "sum(a b) > 0", "sum(a >= 0"} {
+		_, err := compileInvariant(&Invariant{Name: "invalid", Expression: expr})
+		require.Exactly(t, ErrInvalidInvariant, err, expr)
+	}
+}
+
+func TestDB_Claims(t *testing.T) {
+	db, done := getTestingDB(t)
+	defer done()
+
+	accounts := &Policy{
+		Uuid:   "accounts",
+		Claims: []string{"account/"},
+		Specs:  []*OSpec{{Key: &OSpec_Regex{".*"}}},
+	}
+	require.Nil(t, db.AddPolicy(accounts))
+	require.Nil(t, db.AddPolicy(accounts), "policies must be replaceable")
+
+	require.Exactly(t, ErrClaimOverlap, db.AddPolicy(&Policy{
+		Uuid:   "overlap",
+		Claims: []string{"account/alice"},
+		Specs:  []*OSpec{{Key: &OSpec_Regex{".*"}}},
+	}))
+	require.Exactly(t, ErrInvalidClaims, db.AddPolicy(&Policy{
+		Uuid:   "internal",
+		Claims: []string{"__"},
+		Specs:  []*OSpec{{Key: &OSpec_Regex{".*"}}},
+	}))
+
+	s, sign := getTestSpore(db)
+	s.Operations = []*Operation{{Key: "account/alice", Op: Operation_SET, Data: []byte("A")}}
+	sign()
+	require.Exactly(t, ErrOpClaimedKey, db.CanEndorse(s), "claimed keys must only be written under their policy")
+
+	s.Policy = "accounts"
+	sign()
+	require.Nil(t, db.CanEndorse(s))
+
+	s.Operations[0].Key = "unclaimed"
+	sign()
+	require.Exactly(t, ErrOpSharedKey, db.CanEndorse(s), "unclaimed keys matched by several policies must not be written")
+
+	db.RemovePolicy("none")
+	require.Nil(t, db.CanEndorse(s), "unclaimed keys belong to the only policy matching them")
+	require.Nil(t, db.AddPolicy(NonePolicy))
+
+	// Spores of different policies conflict on unclaimed keys
+	s2, sign2 := getTestSpore(db)
+	s2.Operations = []*Operation{{Key: "unclaimed", Op: Operation_SET, Data: []byte("B")}}
+	sign2()
+	require.NotNil(t, s.CheckConflict(s2))
+}
+
+func TestDB_EmitterQuota(t *testing.T) {
+	db, done := getTestingDB(t)
+	defer done()
+
+	pub, _, _ := db.KeyRing.GetPublic("")
+	addTestingPolicies(t, db, &Policy{
+		Uuid:  "quota",
+		Specs: []*OSpec{{Key: &OSpec_Regex{"^[abc]$"}}},
+		EmitterQuota: &EmitterQuota{
+			MaxSize:   5,
+			MaxSpores: 2,
+			Window:    ptypes.DurationProto(time.Hour),
+		},
+	})
+	addTestingPolicies(t, db, &Policy{
+		Uuid:         "staged",
+		Quorum:       2,
+		Endorsers:    []*Endorser{{Public: pub}},
+		Specs:        []*OSpec{{Key: &OSpec_Regex{"^[de]$"}}},
+		EmitterQuota: &EmitterQuota{MaxStaged: 1},
+	})
+	db.Start(false)
+
+	endorse := func(policy, key, data string) error {
+		return db.Endorse(getTestPolicySpore(db, policy, setOp(key, data)))
+	}
+
+	require.Nil(t, endorse("quota", "a", "AAA"))
+	require.Exactly(t, ErrEmitterQuotaExceeded, endorse("quota", "b", "BBB"))
+	require.Nil(t, endorse("quota", "a", "B"), "overwritten bytes must be released")
+
+	usage, err := db.GetEmitterUsage("quota", db.Identity)
+	require.Nil(t, err)
+	require.Exactly(t, EmitterUsage{Size: 1, Spores: 2}, usage)
+	require.Exactly(t, ErrEmitterRateExceeded, endorse("quota", "c", "C"))
+
+	usage, _ = db.GetEmitterUsage("quota", "other")
+	require.Exactly(t, EmitterUsage{}, usage, "quotas must be tracked per emitter")
+
+	require.Nil(t, endorse("staged", "d", "D"))
+	require.Exactly(t, ErrEmitterTooManyStaged, endorse("staged", "e", "E"))
+	usage, _ = db.GetEmitterUsage("staged", db.Identity)
+	require.Exactly(t, uint64(1), usage.Staged)
+}
@@ -0,0 +1,158 @@
+package db
+
+import (
+	"encoding/binary"
+	"errors"
+	"time"
+
+	"gitlab.com/SporeDB/sporedb/db/encoding"
+	"gitlab.com/SporeDB/sporedb/db/operations"
+)
+
+// Emitter usage is tracked on-ledger under internal keys, per policy and emitter.
+// The stored size is updated with the size difference of the keys written by
+// the emitter's spores, and never goes below zero. Applied spores are counted
+// in fixed windows computed from spores' deadlines, so that every node tracks
+// the same value.
+const emitterSizeKeyPrefix = InternalKeyPrefix + "/emitter/size"
+const emitterRateKeyPrefix = InternalKeyPrefix + "/emitter/rate"
+
+// Error messages for emitter quotas.
+var (
+	ErrEmitterQuotaExceeded = errors.New("unable to endorse a spore due to emitter quota reached")
+	ErrEmitterRateExceeded  = errors.New("unable to endorse a spore due to emitter rate limit reached")
+	ErrEmitterTooManyStaged = errors.New("unable to endorse a spore due to too many staged spores from the emitter")
+)
+
+// EmitterUsage is the resource usage of one emitter under a policy.
+type EmitterUsage struct {
+	Size   uint64 // bytes stored
+	Spores uint64 // spores applied in the current window
+	Staged uint64 // spores currently staged
+}
+
+func emitterKey(prefix, policy, emitter string) string {
+	return prefix + "/" + policy + "/" + emitter
+}
+
+// GetEmitterUsage returns the current resource usage of an emitter under a policy.
+// It is thread-safe.
+func (db *DB) GetEmitterUsage(policy, emitter string) (usage EmitterUsage, err error) {
+	p := db.getPolicy(policy)
+	if p == nil {
+		err = ErrUnknownPolicy
+		return
+	}
+
+	usage.Size = db.getEmitterSize(policy, emitter)
+	usage.Spores = db.getEmitterRate(policy, emitter, p.window(), time.Now().Unix())
+	usage.Staged = db.countStaged(policy, emitter, "")
+	return
+}
+
+// checkEmitterQuota checks that the spore fulfills the emitter quota of one of its policies.
+func (db *DB) checkEmitterQuota(s *Spore, p *Policy, oldSize uint64, values map[string]*operations.Value) error {
+	q := p.EmitterQuota
+	if q == nil {
+		return nil
+	}
+
+	if q.MaxSize > 0 {
+		var newSize uint64
+		for _, v := range values {
+			newSize += uint64(len(v.Raw))
+		}
+
+		if addSize(db.getEmitterSize(p.Uuid, s.Emitter), oldSize, newSize) > q.MaxSize {
+			return ErrEmitterQuotaExceeded
+		}
+	}
+
+	if q.MaxSpores > 0 && db.getEmitterRate(p.Uuid, s.Emitter, p.window(), s.Deadline.Seconds) >= q.MaxSpores {
+		return ErrEmitterRateExceeded
+	}
+
+	if q.MaxStaged > 0 && db.countStaged(p.Uuid, s.Emitter, s.Uuid) >= q.MaxStaged {
+		return ErrEmitterTooManyStaged
+	}
+
+	return nil
+}
+
+// updateEmitterUsage returns the internal keys and values tracking the usage
+// of the spore's emitter after its application.
+// It must be called with the Store locked.
+func (db *DB) updateEmitterUsage(s *Spore, p *Policy, oldSize, newSize uint64) (keys []string, values [][]byte) {
+	size := encoding.NewFloat()
+	size.SetUint64(addSize(db.getEmitterSize(p.Uuid, s.Emitter), oldSize, newSize))
+	raw, _ := size.MarshalBinary()
+
+	keys = append(keys, emitterKey(emitterSizeKeyPrefix, p.Uuid, s.Emitter))
+	values = append(values, raw)
+
+	if window := p.window(); window > 0 && s.Deadline != nil {
+		bucket := uint64(s.Deadline.Seconds) / window
+		count := db.getEmitterRate(p.Uuid, s.Emitter, window, s.Deadline.Seconds)
+
+		raw = make([]byte, 16)
+		binary.LittleEndian.PutUint64(raw, bucket)
+		binary.LittleEndian.PutUint64(raw[8:], count+1)
+
+		keys = append(keys, emitterKey(emitterRateKeyPrefix, p.Uuid, s.Emitter))
+		values = append(values, raw)
+	}
+
+	return
+}
+
+func (db *DB) getEmitterSize(policy, emitter string) uint64 {
+	raw, _, _ := db.Store.Get(emitterKey(emitterSizeKeyPrefix, policy, emitter))
+	float, _ := operations.NewValue(raw).Float()
+	size, _ := float.Uint64()
+	return size
+}
+
+// getEmitterRate returns the number of spores applied in the window containing t.
+func (db *DB) getEmitterRate(policy, emitter string, window uint64, t int64) uint64 {
+	if window == 0 {
+		return 0
+	}
+
+	raw, _, _ := db.Store.Get(emitterKey(emitterRateKeyPrefix, policy, emitter))
+	if len(raw) != 16 || binary.LittleEndian.Uint64(raw) != uint64(t)/window {
+		return 0
+	}
+
+	return binary.LittleEndian.Uint64(raw[8:])
+}
+
+// countStaged returns the number of staged spores of the emitter under the policy,
+// ignoring the provided spore uuid.
+func (db *DB) countStaged(policy, emitter, ignore string) (n uint64) {
+	db.stagingMutex.RLock()
+	defer db.stagingMutex.RUnlock()
+
+	for uuid, trigger := range db.staging {
+		s := trigger.spore
+		if uuid != ignore && s.involves(policy) && s.Emitter == emitter {
+			n++
+		}
+	}
+	return
+}
+
+// window returns the rate limiting window of the policy, in seconds.
+func (p *Policy) window() uint64 {
+	w := p.GetEmitterQuota().GetWindow()
+	if w == nil || w.Seconds <= 0 {
+		return 0
+	}
+	return uint64(w.Seconds)
+}
+
+func addSize(usage, oldSize, newSize uint64) uint64 {
+	if usage+newSize < oldSize {
+		return 0
+	}
+	return usage + newSize - oldSize
+}
@@ -0,0 +1,123 @@
+package db
+
+import (
+	"errors"
+	"regexp"
+
+	"gitlab.com/SporeDB/sporedb/db/encoding"
+	"gitlab.com/SporeDB/sporedb/db/operations"
+	"gitlab.com/SporeDB/sporedb/db/schema"
+)
+
+// Error messages for value rules.
+var (
+	ErrInvalidValueRules   = errors.New("the policy contains invalid value rules")
+	ErrValueNotNumeric     = errors.New("the requested value is not numeric according to the policy")
+	ErrValueOutOfRange     = errors.New("the requested value is out of the range allowed by the policy")
+	ErrValueMismatch       = errors.New("the requested value does not match the pattern of the policy")
+	ErrValueTooManyMembers = errors.New("the requested value has too many members for the policy")
+)
+
+// valueChecker holds compiled value rules.
+type valueChecker struct {
+	rules    *ValueRules
+	min, max *encoding.Float
+	regex    *regexp.Regexp
+	schema   *schema.Schema
+}
+
+func (p *Policy) compileRules() (checkers []*valueChecker, err error) {
+	for _, s := range p.Specs {
+		var c *valueChecker
+		if s.Rules != nil {
+			c, err = s.Rules.compile()
+			if err != nil {
+				return
+			}
+		}
+		checkers = append(checkers, c)
+	}
+	return
+}
+
+func (r *ValueRules) compile() (c *valueChecker, err error) {
+	c = &valueChecker{rules: r}
+
+	parseBound := func(s string) (*encoding.Float, error) {
+		if s == "" {
+			return nil, nil
+		}
+		f := encoding.NewFloat()
+		if f.UnmarshalBinary([]byte(s)) != nil {
+			return nil, ErrInvalidValueRules
+		}
+		return f, nil
+	}
+
+	if c.min, err = parseBound(r.Min); err != nil {
+		return
+	}
+	if c.max, err = parseBound(r.Max); err != nil {
+		return
+	}
+	if c.min != nil && c.max != nil && c.min.Cmp(c.max.Float) > 0 {
+		return nil, ErrInvalidValueRules
+	}
+
+	if r.Regex != "" {
+		if c.regex, err = regexp.Compile(r.Regex); err != nil {
+			return nil, ErrInvalidValueRules
+		}
+	}
+
+	if r.JsonSchema != "" {
+		if c.schema, err = schema.Parse([]byte(r.JsonSchema)); err != nil {
+			return nil, ErrInvalidValueRules
+		}
+	}
+
+	return
+}
+
+// check checks the simulated value against the rules.
+// A nil checker accepts every value.
+func (c *valueChecker) check(value *operations.Value) error {
+	if c == nil {
+		return nil
+	}
+
+	if c.rules.Numeric || c.min != nil || c.max != nil {
+		f, err := value.Float()
+		if err != nil {
+			return ErrValueNotNumeric
+		}
+		if c.min != nil && f.Cmp(c.min.Float) < 0 {
+			return ErrValueOutOfRange
+		}
+		if c.max != nil && f.Cmp(c.max.Float) > 0 {
+			return ErrValueOutOfRange
+		}
+	}
+
+	if c.regex != nil && !c.regex.Match(value.Raw) {
+		return ErrValueMismatch
+	}
+
+	if c.schema != nil {
+		if err := c.schema.Validate(value.Raw); err != nil {
+			return err
+		}
+	}
+
+	if c.rules.MaxMembers > 0 {
+		set, err := value.Set()
+		if err != nil {
+			return err
+		}
+		if uint64(len(set.Elements)) > c.rules.MaxMembers {
+			return ErrValueTooManyMembers
+		}
+	}
+
+	return nil
+}
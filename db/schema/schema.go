@@ -0,0 +1,248 @@
+// Package schema provides a minimal JSON Schema validator.
+//
+// Only the following subset of keywords is supported:
+// type, enum, properties, required, additionalProperties, items,
+// minimum, maximum, minLength, maxLength, pattern, minItems and maxItems.
+// Unknown keywords are ignored, as required by the specification.
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"unicode/utf8"
+)
+
+// Error messages for schemas.
+var (
+	ErrInvalidJSON   = errors.New("the value is not valid JSON")
+	ErrInvalidSchema = errors.New("the schema is invalid")
+)
+
+// ValidationError is returned when a JSON document does not match a schema.
+type ValidationError struct {
+	Path   string
+	Reason string
+}
+
+func (e *ValidationError) Error() string {
+	return "schema mismatch at " + e.Path + ": " + e.Reason
+}
+
+// Schema is a parsed JSON schema.
+type Schema struct {
+	Type                 types              `json:"type"`
+	Enum                 []interface{}      `json:"enum"`
+	Properties           map[string]*Schema `json:"properties"`
+	Required             []string           `json:"required"`
+	AdditionalProperties *bool              `json:"additionalProperties"`
+	Items                *Schema            `json:"items"`
+	Minimum              *float64           `json:"minimum"`
+	Maximum              *float64           `json:"maximum"`
+	MinLength            *int               `json:"minLength"`
+	MaxLength            *int               `json:"maxLength"`
+	Pattern              string             `json:"pattern"`
+	MinItems             *int               `json:"minItems"`
+	MaxItems             *int               `json:"maxItems"`
+
+	pattern *regexp.Regexp
+}
+
+// types holds the "type" keyword, which may be either a string or an array.
+type types []string
+
+func (t *types) UnmarshalJSON(data []byte) error {
+	var single string
+	if json.Unmarshal(data, &single) == nil {
+		*t = types{single}
+		return nil
+	}
+
+	var multiple []string
+	if err := json.Unmarshal(data, &multiple); err != nil {
+		return err
+	}
+	*t = multiple
+	return nil
+}
+
+// Parse parses a JSON schema.
+func Parse(data []byte) (*Schema, error) {
+	s := &Schema{}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, ErrInvalidSchema
+	}
+
+	if err := s.compile(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *Schema) compile() (err error) {
+	for _, t := range s.Type {
+		switch t {
+		case "object", "array", "string", "number", "integer", "boolean", "null":
+		default:
+			return ErrInvalidSchema
+		}
+	}
+
+	if s.Pattern != "" {
+		s.pattern, err = regexp.Compile(s.Pattern)
+		if err != nil {
+			return ErrInvalidSchema
+		}
+	}
+
+	for _, p := range s.Properties {
+		if p == nil {
+			return ErrInvalidSchema
+		}
+		if err = p.compile(); err != nil {
+			return
+		}
+	}
+
+	if s.Items != nil {
+		err = s.Items.compile()
+	}
+	return
+}
+
+// Validate checks that the JSON document matches the schema.
+func (s *Schema) Validate(data []byte) error {
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+
+	var v interface{}
+	if err := d.Decode(&v); err != nil || d.More() {
+		return ErrInvalidJSON
+	}
+
+	return s.validate("$", v)
+}
+
+func (s *Schema) validate(path string, v interface{}) error {
+	fail := func(format string, args ...interface{}) error {
+		return &ValidationError{Path: path, Reason: fmt.Sprintf(format, args...)}
+	}
+
+	if len(s.Type) > 0 && !s.Type.match(v) {
+		return fail("expected type %v", []string(s.Type))
+	}
+
+	if len(s.Enum) > 0 && !inEnum(s.Enum, v) {
+		return fail("value not in enum")
+	}
+
+	switch x := v.(type) {
+	case json.Number:
+		f, _ := x.Float64()
+		if s.Minimum != nil && f < *s.Minimum {
+			return fail("%v is lower than %v", f, *s.Minimum)
+		}
+		if s.Maximum != nil && f > *s.Maximum {
+			return fail("%v is greater than %v", f, *s.Maximum)
+		}
+	case string:
+		l := utf8.RuneCountInString(x)
+		if s.MinLength != nil && l < *s.MinLength {
+			return fail("string shorter than %d", *s.MinLength)
+		}
+		if s.MaxLength != nil && l > *s.MaxLength {
+			return fail("string longer than %d", *s.MaxLength)
+		}
+		if s.pattern != nil && !s.pattern.MatchString(x) {
+			return fail("string does not match %q", s.Pattern)
+		}
+	case []interface{}:
+		if s.MinItems != nil && len(x) < *s.MinItems {
+			return fail("less than %d items", *s.MinItems)
+		}
+		if s.MaxItems != nil && len(x) > *s.MaxItems {
+			return fail("more than %d items", *s.MaxItems)
+		}
+		if s.Items != nil {
+			for i, item := range x {
+				if err := s.Items.validate(fmt.Sprintf("%s[%d]", path, i), item); err != nil {
+					return err
+				}
+			}
+		}
+	case map[string]interface{}:
+		for _, r := range s.Required {
+			if _, ok := x[r]; !ok {
+				return fail("missing required property %q", r)
+			}
+		}
+		for k, item := range x {
+			p, ok := s.Properties[k]
+			if !ok {
+				if s.AdditionalProperties != nil && !*s.AdditionalProperties {
+					return fail("unexpected property %q", k)
+				}
+				continue
+			}
+			if err := p.validate(path+"."+k, item); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (t types) match(v interface{}) bool {
+	for _, name := range t {
+		switch x := v.(type) {
+		case nil:
+			if name == "null" {
+				return true
+			}
+		case bool:
+			if name == "boolean" {
+				return true
+			}
+		case string:
+			if name == "string" {
+				return true
+			}
+		case json.Number:
+			if name == "number" {
+				return true
+			}
+			if _, err := x.Int64(); err == nil && name == "integer" {
+				return true
+			}
+		case []interface{}:
+			if name == "array" {
+				return true
+			}
+		case map[string]interface{}:
+			if name == "object" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func inEnum(enum []interface{}, v interface{}) bool {
+	// Numbers are compared by their float value, as enum values are
+	// decoded without json.Number.
+	if n, ok := v.(json.Number); ok {
+		f, _ := n.Float64()
+		v = f
+	}
+
+	for _, e := range enum {
+		if reflect.DeepEqual(e, v) {
+			return true
+		}
+	}
+	return false
+}
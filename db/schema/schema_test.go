@@ -0,0 +1,60 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchema_Validate(t *testing.T) {
+	s, err := Parse([]byte(`{
+		"type": "object",
+		"required": ["name", "tags"],
+		"additionalProperties": false,
+		"properties": {
+			"name": {"type": "string", "minLength": 1, "maxLength": 8, "pattern": "^[a-z]+$"},
+			"age": {"type": "integer", "minimum": 0, "maximum": 150},
+			"role": {"enum": ["admin", "user", 42]},
+			"tags": {"type": "array", "maxItems": 2, "items": {"type": ["string", "null"]}}
+		}
+	}`))
+	require.Nil(t, err)
+
+	valid := []string{
+		`{"name": "alice", "tags": []}`,
+		`{"name": "bob", "age": 42, "role": "admin", "tags": ["a", null]}`,
+		`{"name": "carol", "role": 42, "tags": []}`,
+	}
+	for _, v := range valid {
+		require.Nil(t, s.Validate([]byte(v)), v)
+	}
+
+	invalid := []string{
+		``,
+		`{"name": "alice", "tags": []} {}`,
+		`[]`,
+		`{"tags": []}`,
+		`{"name": "Alice", "tags": []}`,
+		`{"name": "alice-and-bob", "tags": []}`,
+		`{"name": "alice", "age": 4.2, "tags": []}`,
+		`{"name": "alice", "age": -1, "tags": []}`,
+		`{"name": "alice", "role": "root", "tags": []}`,
+		`{"name": "alice", "tags": ["a", "b", "c"]}`,
+		`{"name": "alice", "tags": [1]}`,
+		`{"name": "alice", "tags": [], "extra": true}`,
+	}
+	for _, v := range invalid {
+		require.NotNil(t, s.Validate([]byte(v)), v)
+	}
+}
+
+func TestSchema_Parse(t *testing.T) {
+	_, err := Parse([]byte(`{"type": "unknown"}`))
+	require.Exactly(t, ErrInvalidSchema, err)
+
+	_, err = Parse([]byte(`{"properties": {"a": {"pattern": "("}}}`))
+	require.Exactly(t, ErrInvalidSchema, err)
+
+	_, err = Parse([]byte(`not json`))
+	require.Exactly(t, ErrInvalidSchema, err)
+}
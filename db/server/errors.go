@@ -0,0 +1,153 @@
+package server
+
+import (
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"gitlab.com/SporeDB/sporedb/db"
+	"gitlab.com/SporeDB/sporedb/db/api"
+	"gitlab.com/SporeDB/sporedb/db/encoding"
+	"gitlab.com/SporeDB/sporedb/db/operations"
+	"gitlab.com/SporeDB/sporedb/db/schema"
+	"gitlab.com/SporeDB/sporedb/db/version"
+	"gitlab.com/SporeDB/sporedb/myc/sec"
+)
+
+// errorStatus is the gRPC status code of a database error, and its stable reason.
+type errorStatus struct {
+	code   codes.Code
+	reason string
+}
+
+// Database errors are returned to clients with a gRPC status code, and an
+// api.ErrorDetail holding a stable reason:
+//
+//	NotFound           missing keys, spores or policies
+//	Aborted            conflicts with the state or other spores, to be retried
+//	AlreadyExists      uuids reused by another transaction
+//	FailedPrecondition spores that cannot be endorsed yet
+//	PermissionDenied   operations forbidden by the policy, and foreign endorsers
+//	ResourceExhausted  policy and emitter quotas
+//	InvalidArgument    malformed transactions, policies and evidences
+//	DeadlineExceeded   expired spores
+//	Unauthenticated    invalid signatures
+//
+// Every exported error of package db must be listed, see TestErrorStatuses.
+var errorStatuses = map[error]errorStatus{
+	db.ErrNotFound:       {codes.NotFound, "NOT_FOUND"},
+	db.ErrNoRelatedSpore: {codes.NotFound, "NO_RELATED_SPORE"},
+	db.ErrUnknownPolicy:  {codes.NotFound, "UNKNOWN_POLICY"},
+
+	db.ErrConflictingWithStaging: {codes.Aborted, "CONFLICTING_WITH_STAGING"},
+	db.ErrBehindRequirement:      {codes.Aborted, "BEHIND_REQUIREMENT"},
+	version.ErrVersionMismatch:   {codes.Aborted, "VERSION_MISMATCH"},
+	db.ErrVetoed:                 {codes.Aborted, "VETOED"},
+	db.ErrDuplicatedApplication:  {codes.Aborted, "DUPLICATED_APPLICATION"},
+	db.ErrAlreadyCommitted:       {codes.Aborted, "ALREADY_COMMITTED"},
+	db.ErrDuplicatedCancel:       {codes.Aborted, "DUPLICATED_CANCEL"},
+	db.ErrDuplicatedEndorsement:  {codes.Aborted, "DUPLICATED_ENDORSEMENT"},
+	db.ErrDuplicatedEvidence:     {codes.Aborted, "DUPLICATED_EVIDENCE"},
+
+	ErrUuidReused: {codes.AlreadyExists, "UUID_REUSED"},
+
+	db.ErrPendingDependency: {codes.FailedPrecondition, "PENDING_DEPENDENCY"},
+	db.ErrFailedDependency:  {codes.FailedPrecondition, "FAILED_DEPENDENCY"},
+	db.ErrNotBefore:         {codes.FailedPrecondition, "NOT_BEFORE"},
+
+	db.ErrOpTooLarge:          {codes.PermissionDenied, "OP_TOO_LARGE"},
+	db.ErrOpNotAllowed:        {codes.PermissionDenied, "OP_NOT_ALLOWED"},
+	db.ErrOpDisabledKey:       {codes.PermissionDenied, "OP_DISABLED_KEY"},
+	db.ErrOpSystemKey:         {codes.PermissionDenied, "OP_SYSTEM_KEY"},
+	db.ErrOpClaimedKey:        {codes.PermissionDenied, "OP_CLAIMED_KEY"},
+	db.ErrOpSharedKey:         {codes.PermissionDenied, "OP_SHARED_KEY"},
+	db.ErrValueNotNumeric:     {codes.PermissionDenied, "VALUE_NOT_NUMERIC"},
+	db.ErrValueOutOfRange:     {codes.PermissionDenied, "VALUE_OUT_OF_RANGE"},
+	db.ErrValueMismatch:       {codes.PermissionDenied, "VALUE_MISMATCH"},
+	db.ErrValueTooManyMembers: {codes.PermissionDenied, "VALUE_TOO_MANY_MEMBERS"},
+	schema.ErrInvalidJSON:     {codes.PermissionDenied, "INVALID_JSON"},
+	db.ErrInvalidPolicyUpdate: {codes.PermissionDenied, "INVALID_POLICY_UPDATE"},
+	db.ErrMissingCosignature:  {codes.PermissionDenied, "MISSING_COSIGNATURE"},
+	db.ErrForeignSpore:        {codes.PermissionDenied, "FOREIGN_SPORE"},
+	db.ErrForeignEmitter:      {codes.PermissionDenied, "FOREIGN_EMITTER"},
+	db.ErrUnallowedEndorser:   {codes.PermissionDenied, "UNALLOWED_ENDORSER"},
+	db.ErrExcludedEndorser:    {codes.PermissionDenied, "EXCLUDED_ENDORSER"},
+	ErrAdminDisabled:          {codes.PermissionDenied, "ADMIN_DISABLED"},
+
+	db.ErrPolicyQuotaExceeded:  {codes.ResourceExhausted, "POLICY_QUOTA_EXCEEDED"},
+	db.ErrEmitterQuotaExceeded: {codes.ResourceExhausted, "EMITTER_QUOTA_EXCEEDED"},
+	db.ErrEmitterRateExceeded:  {codes.ResourceExhausted, "EMITTER_RATE_EXCEEDED"},
+	db.ErrEmitterTooManyStaged: {codes.ResourceExhausted, "EMITTER_TOO_MANY_STAGED"},
+
+	db.ErrDeadlineMissing:        {codes.InvalidArgument, "DEADLINE_MISSING"},
+	db.ErrDeadlineTooFar:         {codes.InvalidArgument, "DEADLINE_TOO_FAR"},
+	db.ErrDeadlineBeyondValidity: {codes.InvalidArgument, "DEADLINE_BEYOND_VALIDITY"},
+	db.ErrNotBeforeTooFar:        {codes.InvalidArgument, "NOT_BEFORE_TOO_FAR"},
+	db.ErrNoEmitter:              {codes.InvalidArgument, "NO_EMITTER"},
+	db.ErrOpPolicyNotInvolved:    {codes.InvalidArgument, "OP_POLICY_NOT_INVOLVED"},
+	db.ErrOpPolicyMismatch:       {codes.InvalidArgument, "OP_POLICY_MISMATCH"},
+	db.ErrDuplicatedCosignature:  {codes.InvalidArgument, "DUPLICATED_COSIGNATURE"},
+	operations.ErrNotNumeric:     {codes.InvalidArgument, "NOT_NUMERIC"},
+	operations.ErrNotValidSet:    {codes.InvalidArgument, "NOT_VALID_SET"},
+	encoding.ErrEmptyElement:     {codes.InvalidArgument, "EMPTY_ELEMENT"},
+	ErrInvalidUuid:               {codes.InvalidArgument, "INVALID_UUID"},
+	db.ErrInvalidEvidence:        {codes.InvalidArgument, "INVALID_EVIDENCE"},
+
+	db.ErrPolicyNoUuid:            {codes.InvalidArgument, "POLICY_NO_UUID"},
+	db.ErrPolicyNoEndorser:        {codes.InvalidArgument, "POLICY_NO_ENDORSER"},
+	db.ErrPolicyEmptyEndorser:     {codes.InvalidArgument, "POLICY_EMPTY_ENDORSER"},
+	db.ErrPolicyDuplicateEndorser: {codes.InvalidArgument, "POLICY_DUPLICATE_ENDORSER"},
+	db.ErrPolicyUnsafeQuorum:      {codes.InvalidArgument, "POLICY_UNSAFE_QUORUM"},
+	db.ErrPolicyUnreachableQuorum: {codes.InvalidArgument, "POLICY_UNREACHABLE_QUORUM"},
+	db.ErrPolicyNoSpec:            {codes.InvalidArgument, "POLICY_NO_SPEC"},
+	db.ErrPolicyUnreachableCosign: {codes.InvalidArgument, "POLICY_UNREACHABLE_COSIGN"},
+	db.ErrInvalidValueRules:       {codes.InvalidArgument, "INVALID_VALUE_RULES"},
+	db.ErrInvalidInvariant:        {codes.InvalidArgument, "INVALID_INVARIANT"},
+	db.ErrClaimOverlap:            {codes.InvalidArgument, "CLAIM_OVERLAP"},
+	db.ErrInvalidClaims:           {codes.InvalidArgument, "INVALID_CLAIMS"},
+	schema.ErrInvalidSchema:       {codes.InvalidArgument, "INVALID_SCHEMA"},
+
+	db.ErrDeadlineExpired:    {codes.DeadlineExceeded, "DEADLINE_EXPIRED"},
+	db.ErrGracePeriodExpired: {codes.DeadlineExceeded, "GRACE_PERIOD_EXPIRED"},
+
+	sec.ErrInvalidSignature: {codes.Unauthenticated, "INVALID_SIGNATURE"},
+	sec.ErrKeyRingLocked:    {codes.Unavailable, "KEYRING_LOCKED"},
+}
+
+// toStatus converts a database error to a gRPC status error.
+// Unknown errors are returned unchanged.
+func toStatus(err error) error {
+	s, ok := errorStatuses[err]
+	if !ok {
+		switch err.(type) {
+		case db.ErrInvariantViolated:
+			s, ok = errorStatus{codes.PermissionDenied, "INVARIANT_VIOLATED"}, true
+		case *schema.ValidationError:
+			s, ok = errorStatus{codes.PermissionDenied, "SCHEMA_VIOLATED"}, true
+		case sec.ErrUnknownIdentity:
+			s, ok = errorStatus{codes.Unauthenticated, "UNKNOWN_IDENTITY"}, true
+		case sec.ErrInsufficientTrust:
+			s, ok = errorStatus{codes.Unauthenticated, "INSUFFICIENT_TRUST"}, true
+		}
+	}
+
+	if !ok {
+		return err
+	}
+
+	st, e := status.New(s.code, err.Error()).WithDetails(&api.ErrorDetail{Reason: s.reason})
+	if e != nil {
+		return status.Error(s.code, err.Error())
+	}
+	return st.Err()
+}
+
+// errorInterceptor converts the errors returned by the API to gRPC status errors.
+func errorInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	res, err := handler(ctx, req)
+	if err != nil {
+		err = toStatus(err)
+	}
+	return res, err
+}
@@ -0,0 +1,69 @@
+package server
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"gitlab.com/SporeDB/sporedb/db/schema"
+)
+
+// dbErrors returns the messages of the exported errors of package db, by name.
+func dbErrors(t *testing.T) map[string]string {
+	notTest := func(f os.FileInfo) bool { return !strings.HasSuffix(f.Name(), "_test.go") }
+	pkgs, err := parser.ParseDir(token.NewFileSet(), "..", notTest, 0)
+	require.Nil(t, err)
+
+	errs := map[string]string{}
+	for _, file := range pkgs["db"].Files {
+		ast.Inspect(file, func(n ast.Node) bool {
+			spec, ok := n.(*ast.ValueSpec)
+			if !ok {
+				return true
+			}
+			for i, name := range spec.Names {
+				if !strings.HasPrefix(name.Name, "Err") || !name.IsExported() || i >= len(spec.Values) {
+					continue
+				}
+				call, ok := spec.Values[i].(*ast.CallExpr)
+				if !ok || len(call.Args) != 1 {
+					continue
+				}
+				lit, ok := call.Args[0].(*ast.BasicLit)
+				if !ok {
+					continue
+				}
+				errs[name.Name], _ = strconv.Unquote(lit.Value)
+			}
+			return false
+		})
+	}
+	return errs
+}
+
+func TestErrorStatuses(t *testing.T) {
+	mapped := map[string]bool{}
+	for err := range errorStatuses {
+		mapped[err.Error()] = true
+	}
+
+	errs := dbErrors(t)
+	require.NotEmpty(t, errs)
+	for name, msg := range errs {
+		require.True(t, mapped[msg], "db.%s has no status", name)
+	}
+}
+
+func TestToStatus(t *testing.T) {
+	st, ok := status.FromError(toStatus(&schema.ValidationError{Path: "$.a", Reason: "expected a string"}))
+	require.True(t, ok)
+	require.Exactly(t, codes.PermissionDenied, st.Code())
+}
@@ -2,9 +2,15 @@
 package server
 
 import (
+	"errors"
 	"net"
+	"sync"
 	"time"
 
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	lru "github.com/hashicorp/golang-lru"
+	uuid "github.com/satori/go.uuid"
 	"golang.org/x/net/context"
 	"google.golang.org/grpc"
 
@@ -17,8 +23,27 @@ import (
 type Server struct {
 	DB     *db.DB
 	Listen string
+
+	// Reloader is called to reload node's policies and keyring.
+	// Administration calls are disabled if nil.
+	Reloader func() error
+
+	submitMutex sync.Mutex
+	submitting  map[string]chan struct{} // spores being submitted, closed once done
+	submitted   *lru.Cache               // spores recently submitted, by uuid
 }
 
+// maxSubmittedSpores is the number of submitted spores remembered by the server,
+// to tell retries from reused uuids once they are no longer pending.
+const maxSubmittedSpores = 1024
+
+// Error messages for the API.
+var (
+	ErrAdminDisabled = errors.New("administration calls are disabled")
+	ErrInvalidUuid   = errors.New("the transaction uuid is not a valid uuid")
+	ErrUuidReused    = errors.New("the transaction uuid is already used by another spore")
+)
+
 // Get gets a value from the database.
 func (s *Server) Get(ctx context.Context, key *api.Key) (*api.Value, error) {
 	value, version, err := s.DB.Get(key.Key)
@@ -68,14 +93,313 @@ func (s *Server) Contains(ctx context.Context, kv *api.KeyValue) (*api.Boolean,
 }
 
 // Submit submits a set of operations to the database.
+// Transactions identified by the uuid of a known spore are not submitted again:
+// the receipt of this spore is returned instead, if it holds the same transaction.
 func (s *Server) Submit(ctx context.Context, tx *api.Transaction) (*api.Receipt, error) {
+	spore, err := s.newSpore(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.submitOnce(spore, sameTransaction, func() error { return s.DB.Submit(spore) })
+}
+
+// SubmitSpore submits a spore signed by its emitter, known by the keyring of the node.
+// The deadline of spores with a validity is set by the node, up to the timeout
+// of their policies.
+func (s *Server) SubmitSpore(ctx context.Context, spore *db.Spore) (*api.Receipt, error) {
+	if spore.ValidUntil != nil {
+		validity := time.Until(time.Unix(spore.ValidUntil.Seconds, int64(spore.ValidUntil.Nanos)))
+		timeout, err := s.sporeTimeout(spore.Policy, spore.Policies, validity)
+		if err != nil {
+			return nil, err
+		}
+		spore.NarrowDeadline(timeout)
+	}
+
+	return s.submitOnce(spore, sameSigned, func() error { return s.DB.SubmitSigned(spore) })
+}
+
+// submitOnce submits a spore unless it is already known by the node,
+// and returns its receipt. A known spore is a retry if it is the same as the
+// submitted one, otherwise its uuid is reused and ErrUuidReused is returned.
+func (s *Server) submitOnce(spore *db.Spore, same func(a, b *db.Spore) bool, submit func() error) (*api.Receipt, error) {
+	defer s.lockSubmission(spore.Uuid)()
+
+	if status := s.DB.Status(spore.Uuid); status != db.SporeStatus_UNKNOWN {
+		// Spores done before being submitted through this node cannot be compared
+		if known := s.knownSpore(spore.Uuid); known != nil && !same(known, spore) {
+			return nil, ErrUuidReused
+		}
+		return &api.Receipt{Uuid: spore.Uuid, Status: status}, nil
+	}
+
+	if err := submit(); err != nil {
+		return nil, err
+	}
+
+	s.submitMutex.Lock()
+	if s.submitted == nil {
+		s.submitted, _ = lru.New(maxSubmittedSpores)
+	}
+	s.submitMutex.Unlock()
+	s.submitted.Add(spore.Uuid, spore)
+
+	return &api.Receipt{Uuid: spore.Uuid, Status: s.DB.Status(spore.Uuid)}, nil
+}
+
+// knownSpore returns the pending or recently submitted spore of the uuid, if any.
+func (s *Server) knownSpore(uuid string) *db.Spore {
+	if spore := s.DB.PendingSpore(uuid); spore != nil {
+		return spore
+	}
+
+	s.submitMutex.Lock()
+	submitted := s.submitted
+	s.submitMutex.Unlock()
+	if submitted == nil {
+		return nil
+	}
+
+	if spore, ok := submitted.Get(uuid); ok {
+		return spore.(*db.Spore)
+	}
+	return nil
+}
+
+// sameTransaction returns true if the spores hold the same transaction.
+// Their timeouts, emitters and signatures are set again by every submission.
+func sameTransaction(a, b *db.Spore) bool {
+	transaction := func(s *db.Spore) *db.Spore {
+		return &db.Spore{
+			Uuid:         s.Uuid,
+			Policy:       s.Policy,
+			Policies:     s.Policies,
+			Requirements: s.Requirements,
+			Operations:   s.Operations,
+			Dependencies: s.Dependencies,
+		}
+	}
+
+	return proto.Equal(transaction(a), transaction(b))
+}
+
+// sameSigned returns true if the spores have the same payload, signed by their emitter.
+func sameSigned(a, b *db.Spore) bool {
+	unsigned := func(s *db.Spore) *db.Spore {
+		u := *s
+		u.Signature, u.Cosignatures = nil, nil
+		if u.ValidUntil != nil {
+			u.Deadline = nil // set on submission
+		}
+		return &u
+	}
+
+	return proto.Equal(unsigned(a), unsigned(b))
+}
+
+// lockSubmission waits until no other submission of the spore is running,
+// and returns the function ending this one. Different spores are submitted
+// concurrently.
+func (s *Server) lockSubmission(uuid string) func() {
+	s.submitMutex.Lock()
+	for {
+		running, ok := s.submitting[uuid]
+		if !ok {
+			break
+		}
+
+		s.submitMutex.Unlock()
+		<-running
+		s.submitMutex.Lock()
+	}
+
+	if s.submitting == nil {
+		s.submitting = make(map[string]chan struct{})
+	}
+	done := make(chan struct{})
+	s.submitting[uuid] = done
+	s.submitMutex.Unlock()
+
+	return func() {
+		s.submitMutex.Lock()
+		delete(s.submitting, uuid)
+		s.submitMutex.Unlock()
+		close(done)
+	}
+}
+
+// newSpore builds the spore of a transaction.
+func (s *Server) newSpore(tx *api.Transaction) (*db.Spore, error) {
+	var requested time.Duration
+	if tx.Timeout != nil {
+		var err error
+		requested, err = ptypes.Duration(tx.Timeout)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	timeout, err := s.sporeTimeout(tx.Policy, tx.Policies, requested)
+	if err != nil {
+		return nil, err
+	}
+
 	spore := db.NewSpore()
+	if tx.Uuid != "" {
+		if _, err = uuid.FromString(tx.Uuid); err != nil {
+			return nil, ErrInvalidUuid
+		}
+		spore.Uuid = tx.Uuid
+	}
+
 	spore.Policy = tx.Policy
+	spore.Policies = tx.Policies
 	spore.Requirements = tx.Requirements
 	spore.Operations = tx.Operations
-	spore.SetTimeout(5 * time.Second)
+	spore.Dependencies = tx.Dependencies
+	spore.SetTimeout(timeout)
+
+	if tx.Delay != nil {
+		delay, err := ptypes.Duration(tx.Delay)
+		if err != nil {
+			return nil, err
+		}
+		spore.SetDelay(delay)
+	}
+
+	return spore, nil
+}
+
+// sporeTimeout returns the requested timeout of a spore, capped by every involved policy.
+func (s *Server) sporeTimeout(policy string, policies []string, requested time.Duration) (time.Duration, error) {
+	timeout, err := s.DB.SporeTimeout(policy, requested)
+	for _, p := range policies {
+		if err == nil {
+			timeout, err = s.DB.SporeTimeout(p, timeout)
+		}
+	}
+	return timeout, err
+}
+
+// Prepare builds the spore of a transaction to be cosigned, emitted by this node.
+// The spore is neither signed nor submitted: its timeout shall leave enough time
+// to gather the cosignatures.
+func (s *Server) Prepare(ctx context.Context, tx *api.Transaction) (*db.Spore, error) {
+	spore, err := s.newSpore(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	spore.Emitter = s.DB.Identity
+	return spore, nil
+}
+
+// Cosign adds the signature of this node to a prepared spore.
+func (s *Server) Cosign(ctx context.Context, spore *db.Spore) (*db.Spore, error) {
+	return spore, s.DB.Cosign(spore)
+}
+
+// Propose signs and submits a cosigned spore prepared by this node.
+func (s *Server) Propose(ctx context.Context, spore *db.Spore) (*api.Receipt, error) {
+	if spore.Emitter != s.DB.Identity {
+		return nil, db.ErrForeignEmitter
+	}
+
+	return s.submitOnce(spore, sameSigned, func() error { return s.DB.Submit(spore) })
+}
+
+// Cancel cancels a pending spore submitted to this node.
+func (s *Server) Cancel(ctx context.Context, r *api.Receipt) (*api.Empty, error) {
+	return &api.Empty{}, s.DB.Cancel(r.Uuid)
+}
+
+// Status returns the status of a spore, as known by the node.
+func (s *Server) Status(ctx context.Context, r *api.Receipt) (*api.Receipt, error) {
+	return &api.Receipt{Uuid: r.Uuid, Status: s.DB.Status(r.Uuid)}, nil
+}
+
+// Simulate runs the endorsement checks of a transaction, without submitting it.
+func (s *Server) Simulate(ctx context.Context, tx *api.Transaction) (*api.Simulation, error) {
+	spore, err := s.newSpore(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	spore.Emitter = s.DB.Identity
+	r := s.DB.Simulate(spore)
+
+	simulation := &api.Simulation{
+		Uuid:     spore.Uuid,
+		Values:   r.Values,
+		Conflict: r.Conflict,
+	}
+
+	for _, c := range r.Checks {
+		check := &api.Check{Name: c.Name, Passed: c.Err == nil}
+		if c.Err != nil {
+			check.Error = c.Err.Error()
+		}
+		simulation.Checks = append(simulation.Checks, check)
+	}
+
+	for _, u := range r.Usages {
+		simulation.Usages = append(simulation.Usages, &api.PolicyUsage{
+			Policy:  u.Policy,
+			Size:    u.Size,
+			NewSize: u.NewSize,
+			MaxSize: u.MaxSize,
+			Emitter: &api.EmitterUsage{
+				Size:   u.Emitter.Size,
+				Spores: u.Emitter.Spores,
+				Staged: u.Emitter.Staged,
+			},
+			EmitterNewSize: u.EmitterNewSize,
+		})
+	}
+
+	if err = r.Err(); err != nil {
+		simulation.Error = err.Error()
+	}
+
+	return simulation, nil
+}
+
+// Reload reloads node's policies and keyring, and returns the registered policies.
+func (s *Server) Reload(ctx context.Context, _ *api.Empty) (*api.PolicyList, error) {
+	if s.Reloader == nil {
+		return nil, ErrAdminDisabled
+	}
+
+	if err := s.Reloader(); err != nil {
+		return nil, err
+	}
+
+	return &api.PolicyList{Uuids: s.DB.Policies()}, nil
+}
+
+// Usage returns the resource usage of an emitter under a policy.
+func (s *Server) Usage(ctx context.Context, req *api.UsageRequest) (*api.EmitterUsage, error) {
+	usage, err := s.DB.GetEmitterUsage(req.Policy, req.Emitter)
+	if err != nil {
+		return nil, err
+	}
+
+	return &api.EmitterUsage{
+		Size:   usage.Size,
+		Spores: usage.Spores,
+		Staged: usage.Staged,
+	}, nil
+}
+
+// Evidences returns the equivocation evidences stored by the node.
+func (s *Server) Evidences(ctx context.Context, _ *api.Empty) (*api.EvidenceList, error) {
+	evidences, err := s.DB.Evidences()
+	if err != nil {
+		return nil, err
+	}
 
-	return &api.Receipt{Uuid: spore.Uuid}, s.DB.Submit(spore)
+	return &api.EvidenceList{Evidences: evidences}, nil
 }
 
 // Serve starts the SporeDB GRPC server for clients.
@@ -85,7 +409,7 @@ func (s *Server) Serve() error {
 		return err
 	}
 
-	srv := grpc.NewServer()
+	srv := grpc.NewServer(grpc.UnaryInterceptor(errorInterceptor))
 	api.RegisterSporeDBServer(srv, s)
 	return srv.Serve(lis)
 }
@@ -0,0 +1,118 @@
+package server
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/awnumar/memguard"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+
+	"gitlab.com/SporeDB/sporedb/db"
+	"gitlab.com/SporeDB/sporedb/db/api"
+	"gitlab.com/SporeDB/sporedb/db/drivers/boltdb"
+	"gitlab.com/SporeDB/sporedb/myc/sec"
+)
+
+func getTestingServer(t *testing.T) (s *Server, done func()) {
+	path, err := ioutil.TempDir("", "sporedb_server_")
+	require.Nil(t, err)
+
+	store, err := boltdb.New(filepath.Join(path, "db"))
+	require.Nil(t, err)
+
+	keyRing := sec.NewKeyRingEd25519()
+	password, _ := memguard.NewFromBytes([]byte("password"), true)
+	_ = keyRing.CreatePrivate(password)
+
+	database := db.NewDB(store, "test", keyRing)
+	require.Nil(t, database.AddPolicy(db.NonePolicy))
+
+	s = &Server{DB: database}
+	done = func() {
+		password.Destroy()
+		_ = store.Close()
+		_ = os.RemoveAll(path)
+	}
+	return
+}
+
+func TestServer_SubmitRetry(t *testing.T) {
+	s, done := getTestingServer(t)
+	defer done()
+
+	ctx := context.Background()
+	tx := func(data string) *api.Transaction {
+		return &api.Transaction{
+			Uuid:       "6ba7b810-9dad-11d1-80b4-00c04fd430c8",
+			Policy:     db.NonePolicy.Uuid,
+			Operations: []*db.Operation{{Key: "a", Op: db.Operation_SET, Data: []byte(data)}},
+		}
+	}
+
+	r, err := s.Submit(ctx, tx("A"))
+	require.Nil(t, err)
+	require.Exactly(t, db.SporeStatus_APPLIED, r.Status)
+
+	r, err = s.Submit(ctx, tx("A"))
+	require.Nil(t, err, "a retry must return the receipt of the spore")
+	require.Exactly(t, db.SporeStatus_APPLIED, r.Status)
+
+	_, err = s.Submit(ctx, tx("B"))
+	require.Exactly(t, ErrUuidReused, err)
+}
+
+func TestServer_SubmitSporeRetry(t *testing.T) {
+	s, done := getTestingServer(t)
+	defer done()
+
+	ctx := context.Background()
+	spore := func(data string) *db.Spore {
+		spore := db.NewSpore()
+		spore.Uuid = "6ba7b811-9dad-11d1-80b4-00c04fd430c8"
+		spore.Policy = db.NonePolicy.Uuid
+		spore.Emitter = s.DB.Identity
+		spore.SetTimeout(time.Second)
+		spore.Operations = []*db.Operation{{Key: "a", Op: db.Operation_SET, Data: []byte(data)}}
+		require.Nil(t, spore.Sign(s.DB.KeyRing))
+		return spore
+	}
+
+	signed := spore("A")
+	_, err := s.SubmitSpore(ctx, signed)
+	require.Nil(t, err)
+
+	r, err := s.SubmitSpore(ctx, signed)
+	require.Nil(t, err, "a retry must return the receipt of the spore")
+	require.Exactly(t, db.SporeStatus_APPLIED, r.Status)
+
+	_, err = s.SubmitSpore(ctx, spore("B"))
+	require.Exactly(t, ErrUuidReused, err)
+}
+
+func TestServer_SubmitSporeValidity(t *testing.T) {
+	s, done := getTestingServer(t)
+	defer done()
+
+	ctx := context.Background()
+	spore := db.NewSpore()
+	spore.Policy = db.NonePolicy.Uuid
+	spore.Emitter = s.DB.Identity
+	spore.Operations = []*db.Operation{{Key: "a", Op: db.Operation_SET, Data: []byte("A")}}
+	spore.SetValidity(time.Minute)
+	require.Nil(t, spore.Sign(s.DB.KeyRing))
+	retry := *spore
+
+	// The node sets the deadline of the signed spore
+	r, err := s.SubmitSpore(ctx, spore)
+	require.Nil(t, err)
+	require.Exactly(t, db.SporeStatus_APPLIED, r.Status)
+	require.NotNil(t, spore.Deadline)
+
+	r, err = s.SubmitSpore(ctx, &retry)
+	require.Nil(t, err, "a retry must return the receipt of the spore")
+	require.Exactly(t, db.SporeStatus_APPLIED, r.Status)
+}
@@ -0,0 +1,87 @@
+package db
+
+import (
+	"gitlab.com/SporeDB/sporedb/db/operations"
+)
+
+// Simulation is the outcome of a dry-run endorsement of a spore, explaining
+// its rejection. The checks are run in order, until the first failure.
+type Simulation struct {
+	Checks   []SimulationCheck
+	Values   map[string][]byte // simulated values of the keys written by the spore
+	Usages   []SimulationUsage // resource usage of the spore's policies
+	Conflict string            // uuid of the conflicting staged spore, if any
+}
+
+// SimulationCheck is the outcome of one endorsement check.
+type SimulationCheck struct {
+	Name string
+	Err  error
+}
+
+// SimulationUsage is the resource usage of a policy and of the spore's
+// emitter under this policy, before and after the spore.
+type SimulationUsage struct {
+	Policy         string
+	Size           uint64
+	NewSize        uint64
+	MaxSize        uint64 // zero for unlimited policies
+	Emitter        EmitterUsage
+	EmitterNewSize uint64
+}
+
+// Simulate runs the endorsement checks of the spore against the current state,
+// without storing nor broadcasting anything.
+// It is thread-safe.
+func (db *DB) Simulate(s *Spore) *Simulation {
+	r := &Simulation{}
+	_ = db.checkEndorsement(s, r)
+	return r
+}
+
+// Err returns the error of the failed check, nil if the spore can be endorsed.
+func (r *Simulation) Err() error {
+	if n := len(r.Checks); n > 0 {
+		return r.Checks[n-1].Err
+	}
+	return nil
+}
+
+// check records the outcome of one check, and returns its error.
+// It does nothing but returning the error for nil simulations.
+func (r *Simulation) check(name string, err error) error {
+	if r != nil {
+		r.Checks = append(r.Checks, SimulationCheck{Name: name, Err: err})
+	}
+	return err
+}
+
+func (r *Simulation) setValues(values map[string]*operations.Value) {
+	if r == nil {
+		return
+	}
+
+	r.Values = make(map[string][]byte, len(values))
+	for key, v := range values {
+		r.Values[key] = v.Raw
+	}
+}
+
+func (r *Simulation) setConflict(uuid string) {
+	if r != nil {
+		r.Conflict = uuid
+	}
+}
+
+func (r *Simulation) addUsage(db *DB, s *Spore, p *Policy, pv *policyValues) {
+	if r == nil {
+		return
+	}
+
+	u := SimulationUsage{Policy: p.Uuid}
+	u.Size, u.MaxSize = db.getCurrentPolicyUsage(p.Uuid)
+	u.NewSize = addSize(u.Size, pv.oldSize, pv.newSize)
+	u.Emitter, _ = db.GetEmitterUsage(p.Uuid, s.Emitter)
+	u.EmitterNewSize = addSize(u.Emitter.Size, pv.oldSize, pv.newSize)
+	r.Usages = append(r.Usages, u)
+}
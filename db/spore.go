@@ -10,6 +10,18 @@ import (
 	"github.com/satori/go.uuid"
 )
 
+// Spore timeouts.
+const (
+	// DefaultTimeout is the timeout of spores submitted without requested timeout.
+	DefaultTimeout = 5 * time.Second
+	// DefaultMaxTimeout is the maximum timeout of spores for policies without timeout.
+	DefaultMaxTimeout = time.Minute
+	// DefaultMaxDelay is the maximum delay before the not-before time of spores for policies without max delay.
+	DefaultMaxDelay = time.Minute
+	// MaxClockDrift is the tolerated clock difference between emitters and endorsers.
+	MaxClockDrift = time.Second
+)
+
 // NewSpore instanciates a new spore.
 func NewSpore() *Spore {
 	s := &Spore{}
@@ -28,12 +40,41 @@ func (s *Spore) SetTimeout(t time.Duration) {
 	}
 }
 
-// CheckConflict returns an error if two spores are conflicting.
-func (s *Spore) CheckConflict(s2 *Spore) error {
-	if s.Policy != s2.Policy {
-		return nil
+// SetValidity sets the latest deadline accepted by the emitter, according to current time.
+// The deadline is then set on submission, see NarrowDeadline.
+func (s *Spore) SetValidity(t time.Duration) {
+	validUntil := time.Now().Add(t)
+	s.Deadline = nil
+	s.ValidUntil = &timestamp.Timestamp{
+		Seconds: validUntil.Unix(),
+		Nanos:   int32(validUntil.Nanosecond()),
 	}
+}
 
+// NarrowDeadline sets the deadline of the spore according to current time,
+// without exceeding its validity.
+func (s *Spore) NarrowDeadline(t time.Duration) {
+	s.SetTimeout(t)
+	if s.ValidUntil != nil && deadlineToDuration(s.ValidUntil) < t {
+		s.Deadline = &timestamp.Timestamp{
+			Seconds: s.ValidUntil.Seconds,
+			Nanos:   s.ValidUntil.Nanos,
+		}
+	}
+}
+
+// SetDelay updates the not-before time of the spore according to current time.
+func (s *Spore) SetDelay(d time.Duration) {
+	notBefore := time.Now().Add(d)
+	s.NotBefore = &timestamp.Timestamp{
+		Seconds: notBefore.Unix(),
+		Nanos:   int32(notBefore.Nanosecond()),
+	}
+}
+
+// CheckConflict returns an error if two spores are conflicting.
+// Spores of different policies may conflict on unclaimed keys.
+func (s *Spore) CheckConflict(s2 *Spore) error {
 	for _, op := range s.Operations {
 		for _, op2 := range s2.Operations {
 			if err := op.CheckConflict(op2); err != nil {
@@ -53,6 +94,27 @@ func (s *Spore) checkDeadline() bool {
 	return s.Deadline.Seconds >= time.Now().Unix()
 }
 
+// checkValidity checks that the deadline does not exceed the validity of the spore.
+func (s *Spore) checkValidity() bool {
+	if s.ValidUntil == nil {
+		return true
+	}
+
+	d, v := s.Deadline, s.ValidUntil
+	return d.Seconds < v.Seconds || (d.Seconds == v.Seconds && d.Nanos <= v.Nanos)
+}
+
+// untilNotBefore returns the remaining duration before the spore may be applied.
+func (s *Spore) untilNotBefore() time.Duration {
+	if s.NotBefore == nil {
+		return 0
+	}
+
+	return time.Until(time.Unix(s.NotBefore.Seconds, int64(s.NotBefore.Nanos)))
+}
+
+// checkGracePeriod checks that the spore may still be applied.
+// The grace period starts at the deadline, or at the not-before time if later.
 func (s *Spore) checkGracePeriod(grace *duration.Duration) (bool, time.Time) {
 	if s.Deadline == nil {
 		return true, time.Unix(0, 0)
@@ -62,6 +124,11 @@ func (s *Spore) checkGracePeriod(grace *duration.Duration) (bool, time.Time) {
 		grace = &duration.Duration{}
 	}
 
-	unixTime := s.Deadline.Seconds + grace.Seconds
+	start := s.Deadline.Seconds
+	if s.NotBefore != nil && s.NotBefore.Seconds > start {
+		start = s.NotBefore.Seconds
+	}
+
+	unixTime := start + grace.Seconds
 	return unixTime >= time.Now().Unix(), time.Unix(unixTime, 0)
 }
@@ -14,6 +14,41 @@ var _ = proto.Marshal
 var _ = fmt.Errorf
 var _ = math.Inf
 
+// SporeStatus is the status of a spore, as known by a node.
+// Settled spores are only remembered until the end of their grace period.
+type SporeStatus int32
+
+const (
+	SporeStatus_UNKNOWN   SporeStatus = 0
+	SporeStatus_WAITING   SporeStatus = 1
+	SporeStatus_STAGED    SporeStatus = 2
+	SporeStatus_COMMITTED SporeStatus = 3
+	SporeStatus_APPLIED   SporeStatus = 4
+	SporeStatus_DROPPED   SporeStatus = 5
+)
+
+var SporeStatus_name = map[int32]string{
+	0: "UNKNOWN",
+	1: "WAITING",
+	2: "STAGED",
+	3: "COMMITTED",
+	4: "APPLIED",
+	5: "DROPPED",
+}
+var SporeStatus_value = map[string]int32{
+	"UNKNOWN":   0,
+	"WAITING":   1,
+	"STAGED":    2,
+	"COMMITTED": 3,
+	"APPLIED":   4,
+	"DROPPED":   5,
+}
+
+func (x SporeStatus) String() string {
+	return proto.EnumName(SporeStatus_name, int32(x))
+}
+func (SporeStatus) EnumDescriptor() ([]byte, []int) { return fileDescriptor2, []int{0} }
+
 type Operation_Op int32
 
 const (
@@ -57,11 +92,25 @@ type Spore struct {
 	Deadline     *google_protobuf.Timestamp `protobuf:"bytes,4,opt,name=deadline" json:"deadline,omitempty"`
 	Requirements map[string]*version.V      `protobuf:"bytes,5,rep,name=requirements" json:"requirements,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
 	Operations   []*Operation               `protobuf:"bytes,6,rep,name=operations" json:"operations,omitempty"`
+	// The spore is only applied after its dependencies, identified by uuid.
+	Dependencies []string `protobuf:"bytes,7,rep,name=dependencies" json:"dependencies,omitempty"`
+	// The spore is not applied before this time, even if endorsed earlier.
+	NotBefore *google_protobuf.Timestamp `protobuf:"bytes,8,opt,name=not_before,json=notBefore" json:"not_before,omitempty"`
+	// Additional policies, for spores spanning keys governed by several policies.
+	// The spore is applied once the quorum of each involved policy is reached.
+	Policies []string `protobuf:"bytes,9,rep,name=policies" json:"policies,omitempty"`
 	// The signature of the spore is computed on the spore with an empty signature.
-	// signature = signature by emitter ( hash ( marshal ( spore without signature ) ) )
+	// signature = signature by emitter ( hash ( marshal ( spore without signatures ) ) )
 	//
 	// It is used to check spore's integrity.
 	Signature []byte `protobuf:"bytes,10,opt,name=signature,proto3" json:"signature,omitempty"`
+	// Signatures of other parties, computed on the same hash as the emitter's one.
+	// They are required by policies for some keys.
+	Cosignatures []*Cosignature `protobuf:"bytes,11,rep,name=cosignatures" json:"cosignatures,omitempty"`
+	// The latest deadline accepted by the emitter, for spores signed long before
+	// their submission. The deadline of such spores is then left out of the
+	// signatures, and set by the submitting node, up to this time.
+	ValidUntil *google_protobuf.Timestamp `protobuf:"bytes,12,opt,name=valid_until,json=validUntil" json:"valid_until,omitempty"`
 }
 
 func (m *Spore) Reset()                    { *m = Spore{} }
@@ -111,6 +160,27 @@ func (m *Spore) GetOperations() []*Operation {
 	return nil
 }
 
+func (m *Spore) GetDependencies() []string {
+	if m != nil {
+		return m.Dependencies
+	}
+	return nil
+}
+
+func (m *Spore) GetNotBefore() *google_protobuf.Timestamp {
+	if m != nil {
+		return m.NotBefore
+	}
+	return nil
+}
+
+func (m *Spore) GetPolicies() []string {
+	if m != nil {
+		return m.Policies
+	}
+	return nil
+}
+
 func (m *Spore) GetSignature() []byte {
 	if m != nil {
 		return m.Signature
@@ -118,11 +188,28 @@ func (m *Spore) GetSignature() []byte {
 	return nil
 }
 
+func (m *Spore) GetCosignatures() []*Cosignature {
+	if m != nil {
+		return m.Cosignatures
+	}
+	return nil
+}
+
+func (m *Spore) GetValidUntil() *google_protobuf.Timestamp {
+	if m != nil {
+		return m.ValidUntil
+	}
+	return nil
+}
+
 type Operation struct {
 	Key      string       `protobuf:"bytes,1,opt,name=key" json:"key,omitempty"`
 	Op       Operation_Op `protobuf:"varint,2,opt,name=op,enum=db.Operation_Op" json:"op,omitempty"`
 	Data     []byte       `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`
 	Metadata []byte       `protobuf:"bytes,4,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	// The policy governing the key, among the spore's ones.
+	// Defaults to the main policy of the spore.
+	Policy string `protobuf:"bytes,5,opt,name=policy" json:"policy,omitempty"`
 }
 
 func (m *Operation) Reset()                    { *m = Operation{} }
@@ -158,6 +245,13 @@ func (m *Operation) GetMetadata() []byte {
 	return nil
 }
 
+func (m *Operation) GetPolicy() string {
+	if m != nil {
+		return m.Policy
+	}
+	return ""
+}
+
 type RecoverRequest struct {
 	Key string `protobuf:"bytes,1,opt,name=key" json:"key,omitempty"`
 }
@@ -190,44 +284,136 @@ func (m *Catalog) GetKeys() map[string]*version.V {
 	return nil
 }
 
+type Evidence struct {
+	Emitter         string `protobuf:"bytes,1,opt,name=emitter" json:"emitter,omitempty"`
+	First           *Spore `protobuf:"bytes,2,opt,name=first" json:"first,omitempty"`
+	FirstSignature  []byte `protobuf:"bytes,3,opt,name=first_signature,json=firstSignature,json=firstSignature,proto3" json:"first_signature,omitempty"`
+	Second          *Spore `protobuf:"bytes,4,opt,name=second" json:"second,omitempty"`
+	SecondSignature []byte `protobuf:"bytes,5,opt,name=second_signature,json=secondSignature,json=secondSignature,proto3" json:"second_signature,omitempty"`
+}
+
+func (m *Evidence) Reset()                    { *m = Evidence{} }
+func (m *Evidence) String() string            { return proto.CompactTextString(m) }
+func (*Evidence) ProtoMessage()               {}
+func (*Evidence) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{4} }
+
+func (m *Evidence) GetEmitter() string {
+	if m != nil {
+		return m.Emitter
+	}
+	return ""
+}
+
+func (m *Evidence) GetFirst() *Spore {
+	if m != nil {
+		return m.First
+	}
+	return nil
+}
+
+func (m *Evidence) GetFirstSignature() []byte {
+	if m != nil {
+		return m.FirstSignature
+	}
+	return nil
+}
+
+func (m *Evidence) GetSecond() *Spore {
+	if m != nil {
+		return m.Second
+	}
+	return nil
+}
+
+func (m *Evidence) GetSecondSignature() []byte {
+	if m != nil {
+		return m.SecondSignature
+	}
+	return nil
+}
+
+// Cosignature is the signature of a spore by another party than its emitter.
+type Cosignature struct {
+	Emitter   string `protobuf:"bytes,1,opt,name=emitter" json:"emitter,omitempty"`
+	Signature []byte `protobuf:"bytes,2,opt,name=signature,proto3" json:"signature,omitempty"`
+}
+
+func (m *Cosignature) Reset()                    { *m = Cosignature{} }
+func (m *Cosignature) String() string            { return proto.CompactTextString(m) }
+func (*Cosignature) ProtoMessage()               {}
+func (*Cosignature) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{5} }
+
+func (m *Cosignature) GetEmitter() string {
+	if m != nil {
+		return m.Emitter
+	}
+	return ""
+}
+
+func (m *Cosignature) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*Spore)(nil), "db.Spore")
 	proto.RegisterType((*Operation)(nil), "db.Operation")
 	proto.RegisterType((*RecoverRequest)(nil), "db.RecoverRequest")
 	proto.RegisterType((*Catalog)(nil), "db.Catalog")
+	proto.RegisterEnum("db.SporeStatus", SporeStatus_name, SporeStatus_value)
 	proto.RegisterEnum("db.Operation_Op", Operation_Op_name, Operation_Op_value)
+	proto.RegisterType((*Evidence)(nil), "db.Evidence")
+	proto.RegisterType((*Cosignature)(nil), "db.Cosignature")
 }
 
 func init() { proto.RegisterFile("db/spore.proto", fileDescriptor2) }
 
 var fileDescriptor2 = []byte{
-	// 446 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x52, 0x4d, 0x6f, 0xd3, 0x40,
-	0x10, 0xc5, 0x8e, 0xf3, 0x35, 0x09, 0x91, 0x59, 0x51, 0xb4, 0x32, 0x48, 0x58, 0x3e, 0x85, 0x03,
-	0x1b, 0x29, 0x48, 0x08, 0x71, 0xa9, 0xaa, 0x34, 0xa7, 0x52, 0x22, 0x6d, 0x02, 0xf7, 0x75, 0x3d,
-	0x44, 0x56, 0x6d, 0xef, 0xb2, 0x5e, 0x47, 0xf2, 0xbf, 0xe2, 0x27, 0xf1, 0x53, 0xd0, 0x6e, 0xe2,
-	0xd0, 0xaa, 0x9c, 0x38, 0xf9, 0xcd, 0x7b, 0x33, 0xe3, 0xe7, 0x37, 0x86, 0x59, 0x96, 0x2e, 0x6a,
-	0x25, 0x35, 0x32, 0xa5, 0xa5, 0x91, 0xc4, 0xcf, 0xd2, 0xe8, 0xed, 0x5e, 0xca, 0x7d, 0x81, 0x0b,
-	0xc7, 0xa4, 0xcd, 0x8f, 0x85, 0xc9, 0x4b, 0xac, 0x8d, 0x28, 0xd5, 0xb1, 0x29, 0xa2, 0x59, 0xba,
-	0x38, 0xa0, 0xae, 0x73, 0x59, 0x75, 0xcf, 0xa3, 0x92, 0xfc, 0xf6, 0xa1, 0xbf, 0xb5, 0xeb, 0x08,
-	0x81, 0xa0, 0x69, 0xf2, 0x8c, 0x7a, 0xb1, 0x37, 0x1f, 0x73, 0x87, 0xc9, 0x2b, 0x18, 0x28, 0x59,
-	0xe4, 0x77, 0x2d, 0xf5, 0x1d, 0x7b, 0xaa, 0x08, 0x85, 0x21, 0x96, 0xb9, 0x31, 0xa8, 0x69, 0xcf,
-	0x09, 0x5d, 0x49, 0x3e, 0xc2, 0x28, 0x43, 0x91, 0x15, 0x79, 0x85, 0x34, 0x88, 0xbd, 0xf9, 0x64,
-	0x19, 0xb1, 0xa3, 0x3b, 0xd6, 0xb9, 0x63, 0xbb, 0xce, 0x1d, 0x3f, 0xf7, 0x92, 0x4b, 0x98, 0x6a,
-	0xfc, 0xd9, 0xe4, 0x1a, 0x4b, 0xac, 0x4c, 0x4d, 0xfb, 0x71, 0x6f, 0x3e, 0x59, 0xbe, 0x66, 0x59,
-	0xca, 0x9c, 0x3d, 0xc6, 0x1f, 0xa8, 0xeb, 0xca, 0xe8, 0x96, 0x3f, 0x1a, 0x20, 0xef, 0x01, 0xa4,
-	0x42, 0x2d, 0x4c, 0x2e, 0xab, 0x9a, 0x0e, 0xdc, 0xf8, 0x73, 0x3b, 0xbe, 0xe9, 0x58, 0xfe, 0xa0,
-	0x81, 0xbc, 0x81, 0x71, 0x9d, 0xef, 0x2b, 0x61, 0x1a, 0x8d, 0x14, 0x62, 0x6f, 0x3e, 0xe5, 0x7f,
-	0x89, 0xe8, 0x06, 0x5e, 0x3c, 0x79, 0x1f, 0x09, 0xa1, 0x77, 0x8f, 0xed, 0x29, 0x1f, 0x0b, 0x49,
-	0x0c, 0xfd, 0x83, 0x28, 0x1a, 0x74, 0xe9, 0x4c, 0x96, 0xc0, 0xba, 0x6c, 0xbf, 0xf3, 0xa3, 0xf0,
-	0xd9, 0xff, 0xe4, 0x25, 0xbf, 0x3c, 0x18, 0x9f, 0x4d, 0xfc, 0x73, 0x8b, 0x2f, 0x95, 0x5b, 0x31,
-	0x5b, 0x86, 0x8f, 0x1c, 0xb3, 0x8d, 0xe2, 0xbe, 0x54, 0xf6, 0x34, 0x99, 0x30, 0xc2, 0x65, 0x3d,
-	0xe5, 0x0e, 0x93, 0x08, 0x46, 0x25, 0x1a, 0xe1, 0xf8, 0xc0, 0xf1, 0xe7, 0x3a, 0xb9, 0x04, 0x7f,
-	0xa3, 0xc8, 0x10, 0x7a, 0xdb, 0xf5, 0x2e, 0x7c, 0x46, 0x00, 0x06, 0xab, 0xcd, 0xd7, 0xd5, 0xd5,
-	0x2e, 0xf4, 0x2c, 0x79, 0x75, 0x7d, 0x1d, 0x82, 0x05, 0xb7, 0xdf, 0xbe, 0x84, 0x13, 0x32, 0x82,
-	0x60, 0x6b, 0xa9, 0x97, 0x0e, 0xf1, 0xf5, 0x6d, 0x78, 0x91, 0x24, 0x30, 0xe3, 0x78, 0x27, 0x0f,
-	0xa8, 0x6d, 0x0c, 0x58, 0x9b, 0xa7, 0xb6, 0x93, 0x16, 0x86, 0x2b, 0x61, 0x44, 0x21, 0xf7, 0xe4,
-	0x1d, 0x04, 0xf7, 0xd8, 0xd6, 0xd4, 0x73, 0xa9, 0x5f, 0xd8, 0x6f, 0x38, 0x49, 0xec, 0x06, 0xdb,
-	0xd3, 0xb9, 0x5c, 0x4b, 0xb4, 0x82, 0xf1, 0x99, 0xfa, 0xdf, 0x44, 0xd3, 0x81, 0xfb, 0x95, 0x3e,
-	0xfc, 0x09, 0x00, 0x00, 0xff, 0xff, 0x3f, 0x7b, 0x16, 0x20, 0x0c, 0x03, 0x00, 0x00,
+	// 702 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x53, 0xdd, 0x6a, 0xdb, 0x4c,
+	0x10, 0x8d, 0x64, 0xf9, 0x47, 0x23, 0xc7, 0xd1, 0xb7, 0x7c, 0x29, 0x8b, 0x5b, 0x88, 0xab, 0x9b,
+	0x3a, 0x85, 0xca, 0xe0, 0x40, 0xe9, 0xcf, 0x45, 0x70, 0x6d, 0x11, 0x4c, 0xe2, 0x1f, 0xd6, 0x4e,
+	0x73, 0x53, 0x08, 0xb2, 0xb5, 0x31, 0x22, 0xb6, 0x56, 0x95, 0x56, 0x06, 0xbf, 0x5b, 0x1f, 0xa0,
+	0x37, 0x7d, 0xa7, 0xb2, 0x2b, 0x5b, 0x91, 0x93, 0xd2, 0x40, 0xaf, 0x34, 0x73, 0xe6, 0xe8, 0x30,
+	0x33, 0x7b, 0x06, 0x6a, 0xde, 0xac, 0x15, 0x87, 0x2c, 0xa2, 0x76, 0x18, 0x31, 0xce, 0x90, 0xea,
+	0xcd, 0xea, 0x27, 0x0b, 0xc6, 0x16, 0x4b, 0xda, 0x92, 0xc8, 0x2c, 0xb9, 0x6b, 0x71, 0x7f, 0x45,
+	0x63, 0xee, 0xae, 0xc2, 0x94, 0x54, 0xc7, 0xde, 0xac, 0xb5, 0xa6, 0x51, 0xec, 0xb3, 0x60, 0xf7,
+	0x4d, 0x2b, 0xd6, 0x2f, 0x0d, 0x8a, 0x13, 0x21, 0x87, 0x10, 0x68, 0x49, 0xe2, 0x7b, 0x58, 0x69,
+	0x28, 0x4d, 0x9d, 0xc8, 0x18, 0xbd, 0x80, 0x52, 0xc8, 0x96, 0xfe, 0x7c, 0x83, 0x55, 0x89, 0x6e,
+	0x33, 0x84, 0xa1, 0x4c, 0x57, 0x3e, 0xe7, 0x34, 0xc2, 0x05, 0x59, 0xd8, 0xa5, 0xe8, 0x3d, 0x54,
+	0x3c, 0xea, 0x7a, 0x4b, 0x3f, 0xa0, 0x58, 0x6b, 0x28, 0x4d, 0xa3, 0x5d, 0xb7, 0xd3, 0xee, 0xec,
+	0x5d, 0x77, 0xf6, 0x74, 0xd7, 0x1d, 0xc9, 0xb8, 0xe8, 0x1c, 0xaa, 0x11, 0xfd, 0x9e, 0xf8, 0x11,
+	0x5d, 0xd1, 0x80, 0xc7, 0xb8, 0xd8, 0x28, 0x34, 0x8d, 0xf6, 0x4b, 0xdb, 0x9b, 0xd9, 0xb2, 0x3d,
+	0x9b, 0xe4, 0xaa, 0x4e, 0xc0, 0xa3, 0x0d, 0xd9, 0xfb, 0x01, 0xbd, 0x03, 0x60, 0x21, 0x8d, 0x5c,
+	0xee, 0xb3, 0x20, 0xc6, 0x25, 0xf9, 0xfb, 0xa1, 0xf8, 0x7d, 0xb4, 0x43, 0x49, 0x8e, 0x80, 0x2c,
+	0xa8, 0x7a, 0x34, 0xa4, 0x81, 0x47, 0x83, 0xb9, 0x4f, 0x63, 0x5c, 0x6e, 0x14, 0x9a, 0x3a, 0xd9,
+	0xc3, 0xd0, 0x47, 0x80, 0x80, 0xf1, 0xdb, 0x19, 0xbd, 0x63, 0x11, 0xc5, 0x95, 0x67, 0xa7, 0xd1,
+	0x03, 0xc6, 0xbf, 0x48, 0x32, 0xaa, 0x43, 0x45, 0xae, 0x4a, 0x48, 0xeb, 0x52, 0x3a, 0xcb, 0xd1,
+	0x2b, 0xd0, 0x63, 0x7f, 0x11, 0xb8, 0x3c, 0x89, 0x28, 0x86, 0x86, 0xd2, 0xac, 0x92, 0x07, 0x00,
+	0x9d, 0x41, 0x75, 0xce, 0xb2, 0x34, 0xc6, 0x86, 0x9c, 0xe4, 0x48, 0x4c, 0xd2, 0x7d, 0xc0, 0xc9,
+	0x1e, 0x09, 0x7d, 0x06, 0x63, 0xed, 0x2e, 0x7d, 0xef, 0x36, 0x09, 0xb8, 0xbf, 0xc4, 0xd5, 0x67,
+	0x5b, 0x05, 0x49, 0xbf, 0x16, 0xec, 0xfa, 0x25, 0xfc, 0xf7, 0x64, 0xb9, 0xc8, 0x84, 0xc2, 0x3d,
+	0xdd, 0x6c, 0xcd, 0x20, 0x42, 0xd4, 0x80, 0xe2, 0xda, 0x5d, 0x26, 0x54, 0x5a, 0xc1, 0x68, 0x83,
+	0xbd, 0x33, 0xd2, 0x57, 0x92, 0x16, 0x3e, 0xa9, 0x1f, 0x14, 0xeb, 0xa7, 0x02, 0x7a, 0xb6, 0xf1,
+	0x3f, 0xaa, 0xa8, 0x2c, 0x94, 0x12, 0xb5, 0xb6, 0xb9, 0xf7, 0x3c, 0xf6, 0x28, 0x24, 0x2a, 0x0b,
+	0x85, 0x0f, 0x3d, 0x97, 0xbb, 0xd2, 0x58, 0x55, 0x22, 0x63, 0xb1, 0xce, 0x15, 0xe5, 0xae, 0xc4,
+	0x35, 0x89, 0x67, 0x79, 0xce, 0xa3, 0xc5, 0xbc, 0x47, 0xad, 0x73, 0x50, 0x47, 0x21, 0x2a, 0x43,
+	0x61, 0xe2, 0x4c, 0xcd, 0x03, 0x04, 0x50, 0xea, 0x8e, 0x86, 0xdd, 0xce, 0xd4, 0x54, 0x04, 0xd8,
+	0xe9, 0xf5, 0x4c, 0x10, 0xc1, 0xe0, 0xfa, 0xca, 0x34, 0x50, 0x05, 0xb4, 0x89, 0x80, 0xfe, 0x97,
+	0x11, 0x71, 0x06, 0xe6, 0xb1, 0x65, 0x41, 0x8d, 0xd0, 0x39, 0x5b, 0xd3, 0x48, 0xac, 0x87, 0xc6,
+	0xfc, 0xe9, 0x38, 0xd6, 0x06, 0xca, 0x5d, 0x97, 0xbb, 0x4b, 0xb6, 0x40, 0xa7, 0xa0, 0xdd, 0xd3,
+	0x4d, 0x8c, 0x15, 0xf9, 0x60, 0xc7, 0xf2, 0xc1, 0xd2, 0x92, 0x7d, 0x49, 0x37, 0x5b, 0xcf, 0x4a,
+	0x4a, 0xbd, 0x0b, 0x7a, 0x06, 0xfd, 0xf3, 0xa6, 0x7f, 0x28, 0x50, 0x71, 0xd6, 0xbe, 0x30, 0x2b,
+	0xcd, 0x1f, 0xa4, 0xb2, 0x7f, 0x90, 0x27, 0x50, 0xbc, 0xf3, 0xa3, 0x98, 0x6f, 0xc5, 0xf4, 0xec,
+	0xa2, 0x48, 0x8a, 0xa3, 0x37, 0x70, 0x24, 0x83, 0xdb, 0x07, 0x53, 0xa6, 0xab, 0xaf, 0x49, 0x78,
+	0xb2, 0x43, 0xd1, 0x6b, 0x28, 0xc5, 0x74, 0xce, 0x02, 0x0f, 0x6b, 0x8f, 0xa5, 0xb6, 0x05, 0x74,
+	0x0a, 0x66, 0x1a, 0xe5, 0xc4, 0x8a, 0x52, 0xec, 0x28, 0xc5, 0x33, 0x35, 0xcb, 0x01, 0x23, 0xe7,
+	0xe7, 0xbf, 0x0c, 0xb0, 0x77, 0x2e, 0xea, 0xa3, 0x73, 0x79, 0xfb, 0x0d, 0x0c, 0xd9, 0xc2, 0x84,
+	0xbb, 0x3c, 0x89, 0x91, 0x01, 0xe5, 0xeb, 0xe1, 0xe5, 0x70, 0x74, 0x33, 0x34, 0x0f, 0x44, 0x72,
+	0xd3, 0xe9, 0x4f, 0xfb, 0xc3, 0x0b, 0x53, 0x11, 0xef, 0x3f, 0x99, 0x76, 0x2e, 0x9c, 0x9e, 0xa9,
+	0xa2, 0x43, 0xd0, 0xbb, 0xa3, 0xc1, 0xa0, 0x3f, 0x9d, 0x3a, 0x3d, 0xb3, 0x20, 0x78, 0x9d, 0xf1,
+	0xf8, 0xaa, 0xef, 0xf4, 0x4c, 0x4d, 0x24, 0x3d, 0x32, 0x1a, 0x8f, 0x9d, 0x9e, 0x59, 0x9c, 0x95,
+	0xe4, 0xe9, 0x9c, 0xfd, 0x1e, 0x00, 0xda, 0x9a, 0x89, 0x6f, 0x75, 0x05, 0x00, 0x00,
 }
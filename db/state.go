@@ -0,0 +1,153 @@
+package db
+
+import (
+	"encoding/binary"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"go.uber.org/zap"
+)
+
+// The consensus state of the node is persisted under local keys, which are
+// never transferred to other nodes. Staged spores are stored as long as they
+// are staged, so that a restarted node keeps the promises it made to its peers.
+// Applied and vetoed spores are stored along with the end of their grace
+// period or deadline, to prevent double applications and endorsements.
+
+// LocalKeyPrefix is used to discriminate internal keys specific to the node.
+const LocalKeyPrefix = InternalKeyPrefix + "/local/"
+const stagedKeyPrefix = LocalKeyPrefix + "staged/"
+const appliedKeyPrefix = LocalKeyPrefix + "applied/"
+const vetoedKeyPrefix = LocalKeyPrefix + "vetoed/"
+
+func marshalTime(t time.Time) []byte {
+	raw := make([]byte, 8)
+	binary.LittleEndian.PutUint64(raw, uint64(t.Unix()))
+	return raw
+}
+
+func unmarshalTime(raw []byte) time.Time {
+	if len(raw) != 8 {
+		return time.Unix(0, 0)
+	}
+	return time.Unix(int64(binary.LittleEndian.Uint64(raw)), 0)
+}
+
+// storeStaged persists the staged spore.
+func (db *DB) storeStaged(s *Spore) {
+	raw, err := proto.Marshal(s)
+	if err == nil {
+		err = db.Store.Set(stagedKeyPrefix+s.Uuid, raw, nil)
+	}
+
+	if err != nil {
+		zap.L().Error("Unable to store the staged spore",
+			zap.String("uuid", s.Uuid),
+			zap.Error(err),
+		)
+	}
+}
+
+// storeVetoed persists the vetoed spore's uuid until its deadline.
+func (db *DB) storeVetoed(uuid string, deadline time.Time) {
+	if err := db.Store.Set(vetoedKeyPrefix+uuid, marshalTime(deadline), nil); err != nil {
+		zap.L().Error("Unable to store the vetoed spore",
+			zap.String("uuid", uuid),
+			zap.Error(err),
+		)
+	}
+}
+
+func (db *DB) forget(keys ...string) {
+	if err := db.Store.DeleteBatch(keys); err != nil {
+		zap.L().Error("Unable to delete the consensus state",
+			zap.Strings("keys", keys),
+			zap.Error(err),
+		)
+	}
+}
+
+// restore rebuilds the consensus state persisted in the store.
+// Staged spores are restored under their current policy, with our
+// endorsement only, and dropped if their deadline is over.
+func (db *DB) restore() error {
+	catalog, err := db.Store.List()
+	if err != nil {
+		return err
+	}
+
+	var staged, expired []string
+	now := time.Now()
+
+	db.appliedMutex.Lock()
+	for key := range catalog {
+		switch {
+		case strings.HasPrefix(key, stagedKeyPrefix):
+			staged = append(staged, key)
+		case strings.HasPrefix(key, appliedKeyPrefix):
+			raw, _, _ := db.Store.Get(key)
+			db.applied[strings.TrimPrefix(key, appliedKeyPrefix)] = unmarshalTime(raw)
+		case strings.HasPrefix(key, vetoedKeyPrefix):
+			raw, _, _ := db.Store.Get(key)
+			if deadline := unmarshalTime(raw); deadline.Before(now) {
+				expired = append(expired, key)
+			} else {
+				db.vetoed[strings.TrimPrefix(key, vetoedKeyPrefix)] = deadline
+			}
+		}
+	}
+	db.appliedMutex.Unlock()
+
+	for _, key := range staged {
+		if !db.restoreStaged(key) {
+			expired = append(expired, key)
+		}
+	}
+
+	if len(expired) > 0 {
+		db.forget(expired...)
+	}
+
+	return nil
+}
+
+// restoreStaged restores one staged spore,
+// returning false if it shall be dropped.
+func (db *DB) restoreStaged(key string) bool {
+	raw, _, err := db.Store.Get(key)
+	if err != nil {
+		return false
+	}
+
+	s := &Spore{}
+	if proto.Unmarshal(raw, s) != nil || !s.checkDeadline() {
+		return false
+	}
+
+	db.appliedMutex.Lock()
+	_, applied := db.applied[s.Uuid]
+	_, vetoed := db.vetoed[s.Uuid]
+	db.appliedMutex.Unlock()
+
+	policies := db.getPolicies(s)
+	if applied || vetoed || policies == nil {
+		return false
+	}
+
+	var endorsements []*Endorsement
+	if db.isEndorserOfAny(policies, db.Identity) {
+		e, err := db.endorsement(s)
+		if err != nil {
+			return false
+		}
+		endorsements = append(endorsements, e)
+	}
+
+	zap.L().Info("Restoring staged spore",
+		zap.String("uuid", s.Uuid),
+	)
+
+	db.stage(s, policies, endorsements)
+	return true
+}
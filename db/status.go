@@ -0,0 +1,32 @@
+package db
+
+// Status returns the status of the spore, as known by this node.
+// It is thread-safe.
+func (db *DB) Status(uuid string) SporeStatus {
+	db.waitingMutex.RLock()
+	db.stagingMutex.RLock()
+	t, staged := db.staging[uuid]
+	committed := staged && t.committed
+	_, waiting := db.waiting[uuid]
+	db.stagingMutex.RUnlock()
+	db.waitingMutex.RUnlock()
+
+	switch {
+	case committed:
+		return SporeStatus_COMMITTED
+	case staged:
+		return SporeStatus_STAGED
+	case waiting:
+		return SporeStatus_WAITING
+	}
+
+	db.appliedMutex.Lock()
+	defer db.appliedMutex.Unlock()
+	if _, ok := db.applied[uuid]; ok {
+		return SporeStatus_APPLIED
+	}
+	if _, ok := db.vetoed[uuid]; ok {
+		return SporeStatus_DROPPED
+	}
+	return SporeStatus_UNKNOWN
+}
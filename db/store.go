@@ -17,6 +17,10 @@ type Store interface {
 	Set(key string, value []byte, version *version.V) error
 	// SetBatch executes the given "Set" operations in a atomic way.
 	SetBatch(keys []string, values [][]byte, versions []*version.V) error
+	// Delete removes the specified key.
+	Delete(key string) error
+	// DeleteBatch removes the given keys in a atomic way.
+	DeleteBatch(keys []string) error
 	// List returns the map of keys with their values.
 	List() (map[string]*version.V, error)
 }
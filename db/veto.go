@@ -0,0 +1,226 @@
+package db
+
+import (
+	"bytes"
+	"crypto/sha512"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Conflicting spores may be staged on different endorsers at the same time,
+// preventing any of them from reaching its quorum until their deadlines.
+//
+// To resolve such deadlocks, spores are ordered by priority, the spore with
+// the lowest hash having the highest priority. A staged spore is dead when
+// more than n - quorum endorsers either endorsed conflicting spores of higher
+// priority, or vetoed it. As endorsers only give up their promise for spores
+// of higher priority, a dead spore cannot reach its quorum anymore: it is
+// dropped from the staging list, our endorsement is withdrawn with a Veto
+// message, and waiting spores are re-evaluated by priority order.
+
+// Error messages for vetoes.
+var (
+	ErrVetoed = errors.New("unable to endorse a vetoed spore")
+)
+
+// higherPriority returns true if spore a has a higher priority than spore b.
+func (db *DB) higherPriority(a, b *Spore) bool {
+	return bytes.Compare(db.HashSpore(a), db.HashSpore(b)) < 0
+}
+
+// sortByPriority sorts the triggers from the highest to the lowest priority.
+func (db *DB) sortByPriority(triggers []*dbTrigger) {
+	sort.Slice(triggers, func(i, j int) bool {
+		return db.higherPriority(triggers[i].spore, triggers[j].spore)
+	})
+}
+
+func vetoHash(sporeHash []byte) []byte {
+	hash := sha512.Sum512(append([]byte("veto:"), sporeHash...))
+	return hash[:]
+}
+
+// resolveConflicts drops the dead spores sharing keys with the provided spore
+// from the staging list, including the spore itself.
+// It is thread-safe.
+func (db *DB) resolveConflicts(s *Spore) {
+	var dead []*dbTrigger
+
+	db.waitingMutex.RLock()
+	db.stagingMutex.Lock()
+	candidates := db.stagingIndex.related(s)
+	if trigger, ok := db.staging[s.Uuid]; ok {
+		candidates = append(candidates, trigger)
+	}
+
+	for _, trigger := range candidates {
+		if db.isDead(trigger) {
+			trigger.timer.Stop()
+			db.removeStaging(trigger.spore.Uuid)
+			dead = append(dead, trigger)
+		}
+	}
+	db.stagingMutex.Unlock()
+	db.waitingMutex.RUnlock()
+
+	for _, trigger := range dead {
+		zap.L().Info("Dropping dead spore",
+			zap.String("uuid", trigger.spore.Uuid),
+		)
+
+		db.veto(trigger)
+		go db.release(trigger.spore)
+	}
+}
+
+// isDead returns true if the staged spore cannot reach the quorum of one of its policies anymore.
+// It must be called with both waiting and staging locks.
+func (db *DB) isDead(x *dbTrigger) bool {
+	if x.committed {
+		return false
+	}
+
+	opponents := make(map[string]bool)
+	for _, emitter := range x.vetoes {
+		opponents[emitter] = true
+	}
+
+	for _, index := range []sporeIndex{db.stagingIndex, db.waitingIndex} {
+		for _, y := range index.related(x.spore) {
+			if !db.higherPriority(y.spore, x.spore) || y.spore.CheckConflict(x.spore) == nil {
+				continue
+			}
+
+			for _, e := range y.endorsements {
+				opponents[e.Emitter] = true
+			}
+		}
+	}
+
+	for _, e := range x.endorsements {
+		delete(opponents, e.Emitter)
+	}
+
+	for _, p := range x.policies {
+		if len(p.Endorsers) == 0 {
+			continue
+		}
+
+		var n uint64
+		for emitter := range opponents {
+			if db.isEndorser(p, emitter) {
+				n++
+			}
+		}
+
+		if n+p.Quorum > uint64(len(p.Endorsers)) {
+			return true
+		}
+	}
+	return false
+}
+
+// veto records the spore as vetoed, and broadcasts our veto if we endorsed it.
+func (db *DB) veto(trigger *dbTrigger) {
+	s := trigger.spore
+
+	deadline := time.Unix(s.Deadline.GetSeconds(), 0)
+	db.appliedMutex.Lock()
+	db.vetoed[s.Uuid] = deadline
+	db.appliedMutex.Unlock()
+	db.storeVetoed(s.Uuid, deadline)
+
+	var endorsed bool
+	for _, e := range trigger.endorsements {
+		endorsed = endorsed || e.Emitter == db.Identity
+	}
+
+	if !endorsed {
+		return
+	}
+
+	signature, err := db.KeyRing.Sign(vetoHash(db.HashSpore(s)))
+	if err != nil {
+		zap.L().Error("Unable to sign the veto",
+			zap.String("uuid", s.Uuid),
+			zap.Error(err),
+		)
+		return
+	}
+
+	db.Messages <- &Veto{
+		Uuid:      s.Uuid,
+		Emitter:   db.Identity,
+		Signature: signature,
+	}
+}
+
+// AddVeto registers the incoming veto, withdrawing the related endorsement.
+func (db *DB) AddVeto(v *Veto) error {
+	trigger, err := db.addVetoMap(v, db.staging, &db.stagingMutex)
+	if err == ErrNoRelatedSpore {
+		trigger, err = db.addVetoMap(v, db.waiting, &db.waitingMutex)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if trigger.policies != nil { // staged spore
+		db.resolveConflicts(trigger.spore)
+	}
+	return nil
+}
+
+func (db *DB) addVetoMap(v *Veto, ma map[string]*dbTrigger, mu sync.Locker) (*dbTrigger, error) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	trigger, ok := ma[v.Uuid]
+	if !ok {
+		return nil, ErrNoRelatedSpore
+	}
+
+	for _, emitter := range trigger.vetoes {
+		if emitter == v.Emitter {
+			return nil, ErrDuplicatedEndorsement
+		}
+	}
+
+	emitter := v.Emitter
+	if emitter == db.Identity {
+		emitter = ""
+	}
+
+	pub, _, err := db.KeyRing.GetPublic(emitter)
+	if err != nil {
+		return nil, err
+	}
+
+	if !pubToAnyEndorser(db.triggerPolicies(trigger), pub) {
+		return nil, ErrUnallowedEndorser
+	}
+
+	if err = db.KeyRing.Verify(emitter, vetoHash(db.HashSpore(trigger.spore)), v.Signature); err != nil {
+		zap.L().Warn("Invalid veto",
+			zap.String("uuid", v.Uuid),
+			zap.String("endorser", v.Emitter),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	trigger.vetoes = append(trigger.vetoes, v.Emitter)
+	for i, e := range trigger.endorsements {
+		if e.Emitter == v.Emitter {
+			trigger.endorsements = append(trigger.endorsements[:i], trigger.endorsements[i+1:]...)
+			break
+		}
+	}
+
+	return trigger, nil
+}
@@ -54,6 +54,14 @@ func (m *Mycelium) broadcaster() {
 			c.F = protocol.FnSPORE
 		} else if _, ok := message.(*db.Endorsement); ok {
 			c.F = protocol.FnENDORSE
+		} else if _, ok := message.(*db.Veto); ok {
+			c.F = protocol.FnVETO
+		} else if _, ok := message.(*db.Cancel); ok {
+			c.F = protocol.FnCANCEL
+		} else if _, ok := message.(*db.Evidence); ok {
+			c.F = protocol.FnEVIDENCE
+		} else if _, ok := message.(*db.EndorsementRequest); ok {
+			c.F = protocol.FnENDORSEREQUEST
 		} else if r, ok := message.(*db.RecoverRequest); ok {
 			c.F = protocol.FnRECOVERREQUEST
 			m.StartRecovery(r.Key, m.recoveryQuorum)
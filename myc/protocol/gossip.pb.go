@@ -181,37 +181,55 @@ func (m *Gossip) GetRequest() bool {
 	return false
 }
 
+type SporeRequest struct {
+	Uuid string `protobuf:"bytes,1,opt,name=uuid" json:"uuid,omitempty"`
+}
+
+func (m *SporeRequest) Reset()                    { *m = SporeRequest{} }
+func (m *SporeRequest) String() string            { return proto.CompactTextString(m) }
+func (*SporeRequest) ProtoMessage()               {}
+func (*SporeRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
+
+func (m *SporeRequest) GetUuid() string {
+	if m != nil {
+		return m.Uuid
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterType((*Hello)(nil), "protocol.Hello")
 	proto.RegisterType((*Raw)(nil), "protocol.Raw")
 	proto.RegisterType((*Node)(nil), "protocol.Node")
 	proto.RegisterType((*Nodes)(nil), "protocol.Nodes")
 	proto.RegisterType((*Gossip)(nil), "protocol.Gossip")
+	proto.RegisterType((*SporeRequest)(nil), "protocol.SporeRequest")
 }
 
 func init() { proto.RegisterFile("myc/protocol/gossip.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 333 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x51, 0x4f, 0x4b, 0xfc, 0x30,
-	0x10, 0x25, 0xdb, 0xfd, 0xd7, 0xd9, 0xe5, 0xc7, 0x8f, 0x1c, 0x24, 0x16, 0xc5, 0x52, 0xf6, 0xd0,
-	0x8b, 0x29, 0xac, 0x17, 0x11, 0xef, 0x0a, 0x82, 0x87, 0x20, 0x5e, 0xa5, 0xdd, 0x8e, 0x25, 0xd8,
-	0x6d, 0x6a, 0x93, 0x2a, 0xfd, 0x5e, 0x7e, 0x40, 0x69, 0xda, 0xec, 0xa2, 0x17, 0x4f, 0x9d, 0x37,
-	0xef, 0x4d, 0xdf, 0x9b, 0x09, 0x9c, 0xee, 0xbb, 0x5d, 0x52, 0x37, 0xca, 0xa8, 0x9d, 0x2a, 0x93,
-	0x42, 0x69, 0x2d, 0x6b, 0x6e, 0x31, 0x5d, 0xba, 0x76, 0x70, 0x51, 0x28, 0x55, 0x94, 0x38, 0xe8,
-	0xb2, 0xf6, 0x35, 0x31, 0x72, 0x8f, 0xda, 0xa4, 0xfb, 0x51, 0x1a, 0xb0, 0x3c, 0x4b, 0x3e, 0xb0,
-	0xd1, 0x52, 0x55, 0xee, 0x3b, 0x30, 0xd1, 0x17, 0x81, 0xd9, 0x3d, 0x96, 0xa5, 0xa2, 0x0c, 0x16,
-	0x23, 0xc5, 0x48, 0x48, 0xe2, 0xa9, 0x70, 0x90, 0x06, 0xb0, 0x94, 0x39, 0x56, 0x46, 0x9a, 0x8e,
-	0x4d, 0x42, 0x12, 0xfb, 0xe2, 0x80, 0xe9, 0x35, 0xf8, 0x07, 0x33, 0xe6, 0x85, 0x24, 0x5e, 0x6d,
-	0x03, 0x3e, 0xc4, 0xe1, 0x2e, 0x0e, 0x7f, 0x72, 0x0a, 0x71, 0x14, 0xd3, 0x73, 0x80, 0xba, 0xcd,
-	0x4a, 0xb9, 0x7b, 0x79, 0xc3, 0x8e, 0xf9, 0x21, 0x89, 0xd7, 0xc2, 0x1f, 0x3a, 0x0f, 0xd8, 0xd1,
-	0x33, 0xf0, 0xb5, 0x2c, 0xaa, 0xd4, 0xb4, 0x0d, 0x32, 0x18, 0xd8, 0x43, 0x23, 0x52, 0xe0, 0x89,
-	0xf4, 0x93, 0xfe, 0x07, 0xaf, 0x1f, 0x26, 0x36, 0x54, 0x5f, 0xd2, 0xcd, 0x71, 0x8b, 0x89, 0x4d,
-	0x03, 0xdc, 0x2d, 0xfc, 0x7c, 0xdc, 0x88, 0xc2, 0x34, 0x4f, 0x4d, 0x6a, 0x03, 0xaf, 0x85, 0xad,
-	0xff, 0x30, 0xbc, 0x85, 0xe9, 0xa3, 0xca, 0xf1, 0xc7, 0x2d, 0xc8, 0xaf, 0x5b, 0x30, 0x58, 0xa4,
-	0x79, 0xde, 0xa0, 0xd6, 0xe3, 0x99, 0x1c, 0x8c, 0x2e, 0x61, 0xd6, 0x4f, 0x6b, 0xba, 0x81, 0x59,
-	0xd5, 0x17, 0x8c, 0x84, 0x5e, 0xbc, 0xda, 0xfe, 0xe3, 0xee, 0x0d, 0x79, 0xcf, 0x8b, 0x81, 0x8c,
-	0x6e, 0x60, 0x7e, 0x67, 0x5f, 0x9a, 0x9e, 0xc0, 0x5c, 0xd7, 0xaa, 0x19, 0x07, 0x7c, 0x31, 0xa2,
-	0xde, 0xaa, 0xc1, 0xf7, 0x16, 0xb5, 0xb1, 0x56, 0x4b, 0xe1, 0x60, 0x36, 0xb7, 0x7f, 0xbc, 0xfa,
-	0x0e, 0x00, 0x00, 0xff, 0xff, 0x68, 0xbb, 0xe5, 0xdc, 0x39, 0x02, 0x00, 0x00,
+	// 346 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x51, 0x4d, 0x4b, 0xec, 0x30,
+	0x14, 0x25, 0xd3, 0xf9, 0xea, 0x9d, 0xe1, 0xf1, 0xc8, 0xe2, 0x91, 0x57, 0x14, 0x4b, 0x99, 0x45,
+	0x37, 0xb6, 0x30, 0x6e, 0x44, 0xdc, 0x2b, 0x08, 0x2e, 0xa2, 0xb8, 0x95, 0x76, 0x12, 0x4b, 0xb0,
+	0xd3, 0xd4, 0x26, 0x55, 0xfa, 0xbf, 0xfc, 0x81, 0x92, 0xa4, 0x99, 0x41, 0x37, 0xae, 0x72, 0xcf,
+	0x3d, 0xe7, 0x72, 0xce, 0xbd, 0x81, 0xff, 0xfb, 0x61, 0x97, 0xb7, 0x9d, 0xd4, 0x72, 0x27, 0xeb,
+	0xbc, 0x92, 0x4a, 0x89, 0x36, 0xb3, 0x18, 0x2f, 0x7d, 0x3b, 0x3a, 0xab, 0xa4, 0xac, 0x6a, 0xee,
+	0x74, 0x65, 0xff, 0x92, 0x6b, 0xb1, 0xe7, 0x4a, 0x17, 0xfb, 0x51, 0x1a, 0x11, 0x56, 0xe6, 0xef,
+	0xbc, 0x53, 0x42, 0x36, 0xfe, 0x75, 0x4c, 0xf2, 0x89, 0x60, 0x76, 0xcb, 0xeb, 0x5a, 0x62, 0x02,
+	0x8b, 0x91, 0x22, 0x28, 0x46, 0xe9, 0x94, 0x7a, 0x88, 0x23, 0x58, 0x0a, 0xc6, 0x1b, 0x2d, 0xf4,
+	0x40, 0x26, 0x31, 0x4a, 0x43, 0x7a, 0xc0, 0xf8, 0x12, 0xc2, 0x83, 0x19, 0x09, 0x62, 0x94, 0xae,
+	0xb6, 0x51, 0xe6, 0xe2, 0x64, 0x3e, 0x4e, 0xf6, 0xe8, 0x15, 0xf4, 0x28, 0xc6, 0xa7, 0x00, 0x6d,
+	0x5f, 0xd6, 0x62, 0xf7, 0xfc, 0xca, 0x07, 0x12, 0xc6, 0x28, 0x5d, 0xd3, 0xd0, 0x75, 0xee, 0xf8,
+	0x80, 0x4f, 0x20, 0x54, 0xa2, 0x6a, 0x0a, 0xdd, 0x77, 0x9c, 0x80, 0x63, 0x0f, 0x8d, 0x44, 0x42,
+	0x40, 0x8b, 0x0f, 0xfc, 0x17, 0x02, 0x33, 0x8c, 0x6c, 0x28, 0x53, 0xe2, 0xcd, 0x71, 0x8b, 0x89,
+	0x4d, 0x03, 0x99, 0x5f, 0xf8, 0xe9, 0xb8, 0x11, 0x86, 0x29, 0x2b, 0x74, 0x61, 0x03, 0xaf, 0xa9,
+	0xad, 0x7f, 0x31, 0xbc, 0x86, 0xe9, 0xbd, 0x64, 0xfc, 0xdb, 0x2d, 0xd0, 0x8f, 0x5b, 0x10, 0x58,
+	0x14, 0x8c, 0x75, 0x5c, 0xa9, 0xf1, 0x4c, 0x1e, 0x26, 0xe7, 0x30, 0x33, 0xd3, 0x0a, 0x6f, 0x60,
+	0xd6, 0x98, 0x82, 0xa0, 0x38, 0x48, 0x57, 0xdb, 0x3f, 0x99, 0xff, 0xc3, 0xcc, 0xf0, 0xd4, 0x91,
+	0xc9, 0x15, 0xcc, 0x6f, 0xec, 0x4f, 0xe3, 0x7f, 0x30, 0x57, 0xad, 0xec, 0xc6, 0x81, 0x90, 0x8e,
+	0xc8, 0x58, 0x75, 0xfc, 0xad, 0xe7, 0x4a, 0x5b, 0xab, 0x25, 0xf5, 0x30, 0x49, 0x60, 0xfd, 0x60,
+	0x34, 0xd4, 0x61, 0xb3, 0x6a, 0xdf, 0x0b, 0x36, 0x86, 0xb5, 0x75, 0x39, 0xb7, 0xae, 0x17, 0x5f,
+	0x03, 0x00, 0xf0, 0x7c, 0xb5, 0xf5, 0x5d, 0x02, 0x00, 0x00,
 }
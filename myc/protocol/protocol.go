@@ -34,6 +34,11 @@ const (
 	FnGOSSIP                  = 0x06
 	FnNODES                   = 0x07
 	FnCATALOG                 = 0x08
+	FnVETO                    = 0x09
+	FnEVIDENCE                = 0x0A
+	FnSPOREREQUEST            = 0x0B
+	FnENDORSEREQUEST          = 0x0C
+	FnCANCEL                  = 0x0D
 )
 
 var fnTypes = map[Function]reflect.Type{
@@ -45,6 +50,11 @@ var fnTypes = map[Function]reflect.Type{
 	FnGOSSIP:         reflect.TypeOf(Gossip{}),
 	FnNODES:          reflect.TypeOf(Nodes{}),
 	FnCATALOG:        reflect.TypeOf(db.Catalog{}),
+	FnVETO:           reflect.TypeOf(db.Veto{}),
+	FnEVIDENCE:       reflect.TypeOf(db.Evidence{}),
+	FnSPOREREQUEST:   reflect.TypeOf(SporeRequest{}),
+	FnENDORSEREQUEST: reflect.TypeOf(db.EndorsementRequest{}),
+	FnCANCEL:         reflect.TypeOf(db.Cancel{}),
 }
 
 var fnString = map[Function]string{
@@ -55,6 +65,11 @@ var fnString = map[Function]string{
 	FnGOSSIP:         "gossip",
 	FnNODES:          "nodes",
 	FnCATALOG:        "catalog",
+	FnVETO:           "veto",
+	FnEVIDENCE:       "evidence",
+	FnSPOREREQUEST:   "spore-request",
+	FnENDORSEREQUEST: "endorse-request",
+	FnCANCEL:         "cancel",
 }
 
 func (f Function) String() string {
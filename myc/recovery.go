@@ -34,6 +34,12 @@ func (m *Mycelium) handleRecoverRequest(peer *Peer, request *db.RecoverRequest)
 			return
 		}
 
+		for key := range catalog.Keys {
+			if strings.HasPrefix(key, db.LocalKeyPrefix) {
+				delete(catalog.Keys, key) // node-specific state
+			}
+		}
+
 		zap.L().Info("Sending catalog",
 			zap.String("peer", peer.Identity),
 		)
@@ -42,6 +48,8 @@ func (m *Mycelium) handleRecoverRequest(peer *Peer, request *db.RecoverRequest)
 			F: protocol.FnCATALOG,
 			M: catalog,
 		}
+	} else if strings.HasPrefix(request.Key, db.LocalKeyPrefix) {
+		return
 	} else {
 		var data []byte
 		var v *version.V
@@ -159,6 +167,10 @@ func (m *Mycelium) handleCatalog(peer *Peer, catalog *db.Catalog) {
 	m.mutex.Unlock()
 
 	for k, v := range catalog.Keys {
+		if strings.HasPrefix(k, db.LocalKeyPrefix) {
+			continue
+		}
+
 		_, v2, _ := m.DB.Store.Get(k)
 		if v.Matches(v2) != nil {
 			m.StartRecovery(k, m.recoveryQuorum)
@@ -9,6 +9,10 @@ import (
 	"gitlab.com/SporeDB/sporedb/myc/protocol"
 )
 
+// sporeRequestDelay is the delay given to a spore to show up after its endorsement,
+// before requesting it to the endorsement's sender.
+const sporeRequestDelay = 2 * time.Second
+
 // nolint: gocyclo
 func (m *Mycelium) router(p *Peer) {
 	go p.emitter()
@@ -30,6 +34,16 @@ func (m *Mycelium) router(p *Peer) {
 			go m.handleSpore(p, c.M.(*db.Spore))
 		case protocol.FnENDORSE:
 			go m.handleEndorsement(p, c.M.(*db.Endorsement))
+		case protocol.FnVETO:
+			go m.handleVeto(p, c.M.(*db.Veto))
+		case protocol.FnCANCEL:
+			go m.handleCancel(p, c.M.(*db.Cancel))
+		case protocol.FnSPOREREQUEST:
+			go m.handleSporeRequest(p, c.M.(*protocol.SporeRequest))
+		case protocol.FnENDORSEREQUEST:
+			go m.handleEndorsementRequest(p, c.M.(*db.EndorsementRequest))
+		case protocol.FnEVIDENCE:
+			go m.handleEvidence(p, c.M.(*db.Evidence))
 		case protocol.FnGOSSIP:
 			g := c.M.(*protocol.Gossip)
 			if g.Request {
@@ -77,19 +91,111 @@ func (m *Mycelium) handleSpore(p *Peer, s *db.Spore) {
 }
 
 func (m *Mycelium) handleEndorsement(p *Peer, e *db.Endorsement) {
-	for i := 0; i < 20; i++ { // See https://gitlab.com/SporeDB/sporedb/issues/5
-		err := m.DB.AddEndorsement(e)
-		if err == nil {
-			m.Broadcast(p, &protocol.Call{
-				F: protocol.FnENDORSE,
-				M: e,
-			})
+	err := m.DB.AddEndorsement(e)
+	if err == nil {
+		m.Broadcast(p, &protocol.Call{
+			F: protocol.FnENDORSE,
+			M: e,
+		})
+	} else if err == db.ErrNoRelatedSpore {
+		// The endorsement is buffered by the database, fetch its spore if it does not show up
+		time.AfterFunc(sporeRequestDelay, func() { m.requestSpore(p, e.Uuid) })
+	}
+}
+
+func (m *Mycelium) requestSpore(p *Peer, sporeUUID string) {
+	if m.DB.Knows(sporeUUID) || !m.rContainer.Add(sporeUUID, p.Node) {
+		return
+	}
+
+	call := &protocol.Call{
+		F: protocol.FnSPOREREQUEST,
+		M: &protocol.SporeRequest{Uuid: sporeUUID},
+	}
+
+	data, err := call.Pack()
+	if err != nil {
+		zap.L().Error("Unable to pack message",
+			zap.String("type", call.F.String()),
+			zap.String("step", "requestSpore"),
+			zap.Error(err),
+		)
+		return
+	}
+
+	p.write <- data
+}
+
+func (m *Mycelium) handleSporeRequest(p *Peer, r *protocol.SporeRequest) {
+	ok, data := m.rContainer.IsDelivered(r.Uuid)
+	if !ok {
+		s := m.DB.PendingSpore(r.Uuid)
+		if s == nil {
+			zap.L().Warn("Spore request miss",
+				zap.String("uuid", r.Uuid),
+			)
+			return
+		}
+
+		call := &protocol.Call{F: protocol.FnSPORE, M: s}
+		var err error
+		if data, err = call.Pack(); err != nil {
+			zap.L().Error("Unable to pack message",
+				zap.String("type", call.F.String()),
+				zap.String("step", "handleSporeRequest"),
+				zap.Error(err),
+			)
 			return
 		}
-		if err != db.ErrNoRelatedSpore {
+	}
+
+	p.write <- data
+}
+
+// handleEndorsementRequest sends back the endorsements held for the requested spore,
+// including the ones of recently applied spores.
+func (m *Mycelium) handleEndorsementRequest(p *Peer, r *db.EndorsementRequest) {
+	for _, e := range m.DB.Endorsements(r.Uuid) {
+		call := &protocol.Call{F: protocol.FnENDORSE, M: e}
+		data, err := call.Pack()
+		if err != nil {
+			zap.L().Error("Unable to pack message",
+				zap.String("type", call.F.String()),
+				zap.String("step", "handleEndorsementRequest"),
+				zap.Error(err),
+			)
 			return
 		}
-		time.Sleep(100 * time.Millisecond)
+
+		p.write <- data
+	}
+}
+
+func (m *Mycelium) handleVeto(p *Peer, v *db.Veto) {
+	if nil == m.DB.AddVeto(v) {
+		m.Broadcast(p, &protocol.Call{
+			F: protocol.FnVETO,
+			M: v,
+		})
+	}
+}
+
+func (m *Mycelium) handleCancel(p *Peer, c *db.Cancel) {
+	// Cancellations of committed spores are relayed to the nodes which did not commit them
+	if err := m.DB.AddCancel(c); err == nil || err == db.ErrAlreadyCommitted {
+		m.Broadcast(p, &protocol.Call{
+			F: protocol.FnCANCEL,
+			M: c,
+		})
+	}
+}
+
+func (m *Mycelium) handleEvidence(p *Peer, e *db.Evidence) {
+	if nil == m.DB.AddEvidence(e) {
+		m.Broadcast(p, &protocol.Call{
+			F: protocol.FnEVIDENCE,
+			M: e,
+		})
 	}
 }
 
@@ -329,3 +329,26 @@ func (k *KeyRingEd25519) UnmarshalBinary(data []byte) error {
 
 	return nil
 }
+
+// Reload replaces public keys and signatures from a PEM-armored KeyRing,
+// typically the KeyRing file after a third-party key import.
+// The private key is kept as is, and must be the same in the reloaded KeyRing.
+//
+// This function is thread-safe.
+func (k *KeyRingEd25519) Reload(data []byte) error {
+	fresh := NewKeyRingEd25519()
+	if err := fresh.UnmarshalBinary(data); err != nil {
+		return err
+	}
+
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+
+	if !bytes.Equal(fresh.keys[""].Public, k.keys[""].Public) {
+		return ErrInvalidPublicKey
+	}
+
+	k.keys = fresh.keys
+	k.stale = true
+	return nil
+}
@@ -380,3 +380,25 @@ func TestEd25519_RemovePublic(t *testing.T) {
 	signatures := k.GetSignatures("k2")
 	require.Len(t, signatures, 0, "must remove related signatures")
 }
+
+func TestEd25519_Reload(t *testing.T) {
+	password, _ := memguard.NewFromBytes([]byte("password"), true)
+	defer password.Destroy()
+
+	k := NewKeyRingEd25519()
+	_ = k.UnmarshalBinary([]byte(armoredTestKeyRingEd25519Joined))
+	require.Nil(t, k.UnlockPrivate(password))
+	k.RemovePublic("k0")
+
+	require.Nil(t, k.Reload([]byte(armoredTestKeyRingEd25519Joined)))
+	require.False(t, k.Locked(), "should keep the private key unlocked")
+
+	_, trust, err := k.GetPublic("k0")
+	require.Nil(t, err, "should retrieve reloaded k0's data")
+	require.Exactly(t, TrustHIGH, trust)
+
+	other := NewKeyRingEd25519()
+	_ = other.CreatePrivate(password)
+	data, _ := other.MarshalBinary()
+	require.Exactly(t, ErrInvalidPublicKey, k.Reload(data), "must not replace the private key")
+}
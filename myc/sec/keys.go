@@ -111,6 +111,9 @@ type Exporter interface {
 type Importer interface {
 	encoding.BinaryUnmarshaler
 	Import(data []byte, identity string, trust TrustLevel) error
+	// Reload replaces every public key and signature by the ones of a whole set,
+	// while keeping the current private key.
+	Reload(data []byte) error
 }
 
 // KeyRing shall store private and public keys while providing cryptographic functions.